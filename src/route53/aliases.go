@@ -0,0 +1,187 @@
+package route53
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CloudFrontHostedZoneID is the hosted zone of every CloudFront distribution.
+const CloudFrontHostedZoneID = "Z2FDTNDATAQYW2"
+
+// ELBHostedZoneIDs maps regions to the hosted zone of their classic and
+// application load balancers, named <name>.<region>.elb.amazonaws.com. Add
+// regions here as AWS opens them.
+var ELBHostedZoneIDs = map[string]string{
+	"us-east-1":      "Z35SXDOTRQ7X7K",
+	"us-east-2":      "Z3AADJGX6KTTL2",
+	"us-west-1":      "Z368ELLRRE2KJ0",
+	"us-west-2":      "Z1H1FL5HABSF5",
+	"ca-central-1":   "ZQSVJUPU6J1EY",
+	"eu-central-1":   "Z215JYRZR1TBD5",
+	"eu-west-1":      "Z32O12XQLNTSW2",
+	"eu-west-2":      "ZHURV8PSTC4K8",
+	"eu-west-3":      "Z3Q77PNBQS71R4",
+	"eu-north-1":     "Z23TAZ7KKW0L5I",
+	"ap-northeast-1": "Z14GRHDCWA56QT",
+	"ap-northeast-2": "ZWKZPGTI48KDX",
+	"ap-northeast-3": "Z5LXEXXYW11ES",
+	"ap-southeast-1": "Z1LMS91P8CMLE5",
+	"ap-southeast-2": "Z1GM3OXH4ZPM65",
+	"ap-south-1":     "ZP97RAFLXTNZK",
+	"sa-east-1":      "Z2P70J7HTTTPLU",
+}
+
+// NLBHostedZoneIDs maps regions to the hosted zone of their network load
+// balancers, which are named <name>.elb.<region>.amazonaws.com instead. Add
+// regions here as AWS opens them.
+var NLBHostedZoneIDs = map[string]string{
+	"us-east-1":      "Z26RNL4JYFTOTI",
+	"us-east-2":      "ZLMOA37VPKANP",
+	"us-west-1":      "Z24FKFUX50B4VW",
+	"us-west-2":      "Z18D5FSROUN65G",
+	"ca-central-1":   "Z2EPGBW3API2WT",
+	"eu-central-1":   "Z3F0SRJ5LGBH90",
+	"eu-west-1":      "Z2IFOLAFXWLO4F",
+	"eu-west-2":      "ZD4D7Y8KGAS4G",
+	"eu-west-3":      "Z1CMS0P5QUZ6D5",
+	"eu-north-1":     "Z1UDT6IFJ4EJM",
+	"ap-northeast-1": "Z31USIVHYNEOWT",
+	"ap-northeast-2": "ZIBE1TIR4HY56",
+	"ap-northeast-3": "Z1GWIQ4HH19I5X",
+	"ap-southeast-1": "ZKVM4W9LS7TM",
+	"ap-southeast-2": "ZCT6FZBF4DROD",
+	"ap-south-1":     "ZVDDRBQ08TROA",
+	"sa-east-1":      "ZTK26PT1VY4CU",
+}
+
+// S3WebsiteEndpoint is the hosted zone and website endpoint of S3 buckets in
+// a region.
+type S3WebsiteEndpoint struct {
+	HostedZoneID string
+	Endpoint     string
+}
+
+// S3WebsiteEndpoints maps regions to their S3 website endpoints. Add regions
+// here as AWS opens them.
+var S3WebsiteEndpoints = map[string]S3WebsiteEndpoint{
+	"us-east-1":      {"Z3AQBSTGFYJSTF", "s3-website-us-east-1.amazonaws.com."},
+	"us-east-2":      {"Z2O1EMRO9K5GLX", "s3-website.us-east-2.amazonaws.com."},
+	"us-west-1":      {"Z2F56UZL2M1ACD", "s3-website-us-west-1.amazonaws.com."},
+	"us-west-2":      {"Z3BJ6K6RIION7M", "s3-website-us-west-2.amazonaws.com."},
+	"ca-central-1":   {"Z1QDHH18159H29", "s3-website.ca-central-1.amazonaws.com."},
+	"eu-central-1":   {"Z21DNDUVLTQW6Q", "s3-website.eu-central-1.amazonaws.com."},
+	"eu-west-1":      {"Z1BKCTXD74EZPE", "s3-website-eu-west-1.amazonaws.com."},
+	"eu-west-2":      {"Z3GKZC51ZF0DB4", "s3-website.eu-west-2.amazonaws.com."},
+	"eu-west-3":      {"Z3R1K369G5AVDG", "s3-website.eu-west-3.amazonaws.com."},
+	"eu-north-1":     {"Z3BAZG2TWCNX0D", "s3-website.eu-north-1.amazonaws.com."},
+	"ap-northeast-1": {"Z2M4EHUR26P7ZW", "s3-website-ap-northeast-1.amazonaws.com."},
+	"ap-northeast-2": {"Z3W03O7B5YMIYP", "s3-website.ap-northeast-2.amazonaws.com."},
+	"ap-southeast-1": {"Z3O0J2DXBE1FTB", "s3-website-ap-southeast-1.amazonaws.com."},
+	"ap-southeast-2": {"Z1WCIGYICN2BYD", "s3-website-ap-southeast-2.amazonaws.com."},
+	"ap-south-1":     {"Z11RGJOFQNVJUP", "s3-website.ap-south-1.amazonaws.com."},
+	"sa-east-1":      {"Z7KQH4QJS55SO", "s3-website-sa-east-1.amazonaws.com."},
+}
+
+// AliasToELB points at a load balancer in region by its DNS name, e.g.
+// my-lb-1234567890.us-east-1.elb.amazonaws.com for a classic or application
+// load balancer, or my-nlb-1234567890abcdef.elb.us-east-1.amazonaws.com for a
+// network load balancer. The hosted zone is chosen by the form of the name.
+func AliasToELB(region, dnsName string) (AliasTarget, error) {
+	zones := ELBHostedZoneIDs
+	switch {
+	case IsSubdomain(dnsName, "elb."+region+".amazonaws.com"):
+		zones = NLBHostedZoneIDs
+	case !IsSubdomain(dnsName, region+".elb.amazonaws.com"):
+		return AliasTarget{}, fmt.Errorf("%s is not the name of a load balancer in region %s", dnsName, region)
+	}
+
+	zoneID, ok := zones[region]
+	if !ok {
+		return AliasTarget{}, fmt.Errorf("no load balancer hosted zone known for region %s", region)
+	}
+
+	return AliasTarget{HostedZoneID: zoneID, DNSName: CanonicalName(dnsName)}, nil
+}
+
+// AliasToS3Website points at the S3 website endpoint of region. The record
+// set's name must match the name of the bucket.
+func AliasToS3Website(region string) (AliasTarget, error) {
+	endpoint, ok := S3WebsiteEndpoints[region]
+	if !ok {
+		return AliasTarget{}, fmt.Errorf("no S3 website endpoint known for region %s", region)
+	}
+
+	return AliasTarget{HostedZoneID: endpoint.HostedZoneID, DNSName: endpoint.Endpoint}, nil
+}
+
+// AliasToCloudFront points at a distribution by its domain name, e.g.
+// d111111abcdef8.cloudfront.net.
+func AliasToCloudFront(distribution string) AliasTarget {
+	return AliasTarget{HostedZoneID: CloudFrontHostedZoneID, DNSName: CanonicalName(distribution)}
+}
+
+// AliasToRecordInZone points at another record set of zone; name may be
+// relative to the zone.
+func AliasToRecordInZone(zone HostedZone, name string) AliasTarget {
+	return AliasTarget{
+		HostedZoneID: strings.Replace(zone.ID, "/hostedzone/", "", -1),
+		DNSName:      CanonicalName(AbsoluteName(name, zone.Name)),
+	}
+}
+
+// aliasTargetProblems checks an alias against what is known about the AWS
+// service it points at.
+func aliasTargetProblems(rrtype string, alias AliasTarget) []string {
+	problems := []string{}
+	zoneID := strings.Replace(alias.HostedZoneID, "/hostedzone/", "", -1)
+	service := ""
+
+	switch {
+	case zoneID == CloudFrontHostedZoneID:
+		service = "CloudFront"
+		if !IsSubdomain(alias.DNSName, "cloudfront.net") {
+			problems = append(problems, "CloudFront alias target must be a cloudfront.net name")
+		}
+		if alias.EvaluateTargetHealth {
+			problems = append(problems, "CloudFront alias targets cannot evaluate target health")
+		}
+	case isS3WebsiteZone(zoneID):
+		service = "S3 website"
+		for _, endpoint := range S3WebsiteEndpoints {
+			if endpoint.HostedZoneID == zoneID && !NamesEqual(endpoint.Endpoint, alias.DNSName) {
+				problems = append(problems, fmt.Sprintf("S3 website alias target must be %s", endpoint.Endpoint))
+			}
+		}
+	case isELBZone(zoneID):
+		service = "load balancer"
+	}
+
+	if service != "" && rrtype != "A" && rrtype != "AAAA" {
+		problems = append(problems, fmt.Sprintf("%s alias targets need an A or AAAA record", service))
+	}
+
+	return problems
+}
+
+func isS3WebsiteZone(zoneID string) bool {
+	for _, endpoint := range S3WebsiteEndpoints {
+		if endpoint.HostedZoneID == zoneID {
+			return true
+		}
+	}
+	return false
+}
+
+func isELBZone(zoneID string) bool {
+	for _, id := range ELBHostedZoneIDs {
+		if id == zoneID {
+			return true
+		}
+	}
+	for _, id := range NLBHostedZoneIDs {
+		if id == zoneID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package route53
+
+import "testing"
+
+func TestAliasToELB(t *testing.T) {
+	tests := []struct {
+		region, dnsName, zoneID string
+	}{
+		{"us-east-1", "my-lb-1234567890.us-east-1.elb.amazonaws.com", "Z35SXDOTRQ7X7K"},
+		{"us-east-1", "dualstack.my-lb-1234567890.us-east-1.elb.amazonaws.com.", "Z35SXDOTRQ7X7K"},
+		{"eu-west-1", "internal-my-alb-1234567890.eu-west-1.elb.amazonaws.com", "Z32O12XQLNTSW2"},
+		{"us-east-1", "my-nlb-1234567890abcdef.elb.us-east-1.amazonaws.com", "Z26RNL4JYFTOTI"},
+		{"eu-west-1", "My-NLB-1234567890abcdef.ELB.eu-west-1.amazonaws.com.", "Z2IFOLAFXWLO4F"},
+		{"us-east-1", "dualstack.my-nlb-1234567890abcdef.elb.us-east-1.amazonaws.com", "Z26RNL4JYFTOTI"},
+		{"us-east-1", "my-lb-1234567890.us-west-2.elb.amazonaws.com", ""},
+		{"us-east-1", "my-nlb-1234567890abcdef.elb.us-west-2.amazonaws.com", ""},
+		{"us-east-1", "www.example.com", ""},
+		{"xx-nowhere-1", "my-lb-1234567890.xx-nowhere-1.elb.amazonaws.com", ""},
+		{"xx-nowhere-1", "my-nlb-1234567890abcdef.elb.xx-nowhere-1.amazonaws.com", ""},
+	}
+
+	for _, test := range tests {
+		alias, err := AliasToELB(test.region, test.dnsName)
+		if test.zoneID == "" {
+			if err == nil {
+				t.Errorf("%s %s: expected an error, got %+v", test.region, test.dnsName, alias)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: %s", test.region, test.dnsName, err)
+			continue
+		}
+		if alias.HostedZoneID != test.zoneID || alias.DNSName != CanonicalName(test.dnsName) {
+			t.Errorf("%s %s: got %+v, want zone %s", test.region, test.dnsName, alias, test.zoneID)
+		}
+	}
+
+	if !isELBZone("Z26RNL4JYFTOTI") || !isELBZone("Z35SXDOTRQ7X7K") || isELBZone(CloudFrontHostedZoneID) {
+		t.Error("isELBZone does not cover both load balancer tables")
+	}
+}
+
+func TestAliasToS3Website(t *testing.T) {
+	alias, err := AliasToS3Website("eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alias.HostedZoneID != "Z1BKCTXD74EZPE" || alias.DNSName != "s3-website-eu-west-1.amazonaws.com." {
+		t.Errorf("unexpected alias %+v", alias)
+	}
+	if problems := aliasTargetProblems("A", alias); len(problems) != 0 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+
+	if alias, err := AliasToS3Website("xx-nowhere-1"); err == nil {
+		t.Errorf("unknown region: expected an error, got %+v", alias)
+	}
+}
+
+func TestAliasToCloudFront(t *testing.T) {
+	alias := AliasToCloudFront("D111111ABCDEF8.cloudfront.net")
+	if alias.HostedZoneID != CloudFrontHostedZoneID || alias.DNSName != "d111111abcdef8.cloudfront.net." {
+		t.Errorf("unexpected alias %+v", alias)
+	}
+	if problems := aliasTargetProblems("A", alias); len(problems) != 0 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+	if problems := aliasTargetProblems("CNAME", alias); len(problems) != 1 {
+		t.Errorf("CNAME to CloudFront: problems %v", problems)
+	}
+}
+
+func TestAliasToRecordInZone(t *testing.T) {
+	zone := HostedZone{ID: "/hostedzone/Z1", Name: "example.com."}
+	tests := []struct {
+		name, dnsName string
+	}{
+		{"www", "www.example.com."},
+		{"WWW.Example.com.", "www.example.com."},
+		{"@", "example.com."},
+	}
+
+	for _, test := range tests {
+		alias := AliasToRecordInZone(zone, test.name)
+		if alias.HostedZoneID != "Z1" || alias.DNSName != test.dnsName {
+			t.Errorf("%q: got %+v, want %s", test.name, alias, test.dnsName)
+		}
+	}
+}
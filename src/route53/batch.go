@@ -0,0 +1,91 @@
+package route53
+
+// Limits on a single ChangeResourceRecordSets request. UPSERT changes count
+// twice towards the record and character limits.
+const (
+	maxBatchChanges    = 100
+	maxBatchRecords    = 1000
+	maxBatchValueChars = 32000
+)
+
+// SplitRRSetChanges splits changes into batches that each fit within the
+// Route53 limits for a single change request, preserving their order.
+func SplitRRSetChanges(changes []RRSetChange) [][]RRSetChange {
+	batches := [][]RRSetChange{}
+	batch := []RRSetChange{}
+	records, chars := 0, 0
+
+	for _, change := range changes {
+		changeRecords, changeChars := 1, 0
+		if change.RRSet.ResourceRecords != nil {
+			changeRecords = len(change.RRSet.ResourceRecords.ResourceRecord)
+			for _, rr := range change.RRSet.ResourceRecords.ResourceRecord {
+				changeChars += len(rr.Value)
+			}
+		}
+		if change.Action == "UPSERT" {
+			changeRecords *= 2
+			changeChars *= 2
+		}
+
+		if len(batch) > 0 && (len(batch) == maxBatchChanges ||
+			records+changeRecords > maxBatchRecords ||
+			chars+changeChars > maxBatchValueChars) {
+			batches = append(batches, batch)
+			batch = []RRSetChange{}
+			records, chars = 0, 0
+		}
+
+		batch = append(batch, change)
+		records += changeRecords
+		chars += changeChars
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// ChangeRRSetBatches submits changes in as many requests as the Route53
+// limits require. The changes of earlier batches stay applied if a later one
+// fails; the ChangeInfo of every submitted batch is returned either way.
+func (r53 *Route53) ChangeRRSetBatches(zoneID string, changes []RRSetChange, comment string) ([]ChangeInfo, error) {
+	infos := []ChangeInfo{}
+
+	for _, batch := range SplitRRSetChanges(changes) {
+		info, err := r53.ChangeRRSet(zoneID, batch, comment)
+		if err != nil {
+			return infos, err
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// Convenience functions on AWS APIs.
+
+func (z *HostedZone) ChangeRRSetBatches(changes []RRSetChange, comment string) ([]ChangeInfo, error) {
+	return z.changeRRSetBatches(changes, comment, nil)
+}
+
+// changeRRSetBatches is ChangeRRSetBatches for callers that may have just
+// listed the zone. If current is not nil, a registry checks ownership against
+// it instead of looking each record set up.
+func (z *HostedZone) changeRRSetBatches(changes []RRSetChange, comment string, current []RRSet) ([]ChangeInfo, error) {
+	if z.r53.Registry != nil {
+		var err error
+		if current != nil {
+			changes, err = z.r53.Registry.trackListed(current, changes)
+		} else {
+			changes, err = z.r53.Registry.track(z.r53, z.ID, changes)
+		}
+		if err != nil {
+			return []ChangeInfo{}, err
+		}
+	}
+
+	return z.r53.ChangeRRSetBatches(z.ID, changes, comment)
+}
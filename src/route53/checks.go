@@ -2,8 +2,8 @@ package route53
 
 import (
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -17,11 +17,12 @@ type CreateHealthCheckRequest struct {
 }
 
 type HealthCheckConfig struct {
-	IPAddress                string
-	Port                     uint16
+	IPAddress                string `xml:",omitempty"`
+	Port                     uint16 `xml:",omitempty"`
 	Type                     string
-	ResourcePath             string
-	FullyQualifiedDomainName string
+	ResourcePath             string `xml:",omitempty"`
+	FullyQualifiedDomainName string `xml:",omitempty"`
+	SearchString             string `xml:",omitempty"`
 }
 
 type CreateHealthCheckResponse struct {
@@ -30,7 +31,8 @@ type CreateHealthCheckResponse struct {
 }
 
 type HealthCheck struct {
-	ID                string `xml:"Id"`
+	r53               *Route53 `xml:"-"`
+	ID                string   `xml:"Id"`
 	CallerReference   string
 	HealthCheckConfig HealthCheckConfig
 }
@@ -41,8 +43,8 @@ type GetHealthCheckResponse struct {
 }
 
 type ListHealthChecksResponse struct {
-	XMLName      xml.Name `xml:"ListHealthChecksResponse"`
-	HealthChecks []HealthCheck
+	XMLName      xml.Name      `xml:"ListHealthChecksResponse"`
+	HealthChecks []HealthCheck `xml:"HealthChecks>HealthCheck"`
 	IsTruncated  bool
 	Marker       string
 	NextMarker   string
@@ -57,14 +59,14 @@ type DeleteHealthCheckResponse struct {
 
 func (r53 *Route53) CreateHealthCheck(config HealthCheckConfig, reference string) (string, error) {
 	xmlReq := &CreateHealthCheckRequest{
-		XMLNS:             "https://route53.amazonaws.com/doc/2012-12-12/",
+		XMLNS:             "https://route53.amazonaws.com/doc/2013-04-01/",
 		CallerReference:   reference,
 		HealthCheckConfig: config,
 	}
 
 	req := request{
 		method: "POST",
-		path:   "/2012-12-12/healthcheck",
+		path:   "/2013-04-01/healthcheck",
 		body:   xmlReq,
 	}
 
@@ -80,7 +82,7 @@ func (r53 *Route53) CreateHealthCheck(config HealthCheckConfig, reference string
 func (r53 *Route53) GetHealthCheck(id string) (HealthCheck, error) {
 	req := request{
 		method: "GET",
-		path:   fmt.Sprintf("/2012-12-12/healthcheck/%s", strings.Replace(id, "/healthcheck/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/healthcheck/%s", strings.Replace(id, "/healthcheck/", "", -1)),
 	}
 
 	xmlRes := &GetHealthCheckResponse{}
@@ -88,6 +90,7 @@ func (r53 *Route53) GetHealthCheck(id string) (HealthCheck, error) {
 	if err := r53.run(req, xmlRes); err != nil {
 		return HealthCheck{}, err
 	}
+	xmlRes.HealthCheck.r53 = r53
 
 	return xmlRes.HealthCheck, nil
 }
@@ -95,25 +98,41 @@ func (r53 *Route53) GetHealthCheck(id string) (HealthCheck, error) {
 func (r53 *Route53) ListHealthChecks() ([]HealthCheck, error) {
 	req := request{
 		method: "GET",
-		path:   "/2012-12-12/healthcheck",
+		path:   "/2013-04-01/healthcheck",
 	}
 
 	xmlRes := &ListHealthChecksResponse{}
 
+	checks := []HealthCheck{}
+
 	if err := r53.run(req, xmlRes); err != nil {
 		return []HealthCheck{}, err
 	}
-	if xmlRes.IsTruncated {
-		return []HealthCheck{}, errors.New("cannot handle truncated response")
+	checks = append(checks, xmlRes.HealthChecks...)
+
+	for xmlRes.IsTruncated {
+		req.params = &url.Values{
+			"marker": []string{xmlRes.NextMarker},
+		}
+
+		xmlRes = &ListHealthChecksResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []HealthCheck{}, err
+		}
+		checks = append(checks, xmlRes.HealthChecks...)
+	}
+
+	for i := range checks {
+		checks[i].r53 = r53
 	}
 
-	return xmlRes.HealthChecks, nil
+	return checks, nil
 }
 
 func (r53 *Route53) DeleteHealthCheck(id string) error {
 	req := request{
 		method: "DELETE",
-		path:   fmt.Sprintf("/2012-12-12/healthcheck/%s", strings.Replace(id, "/healthcheck/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/healthcheck/%s", strings.Replace(id, "/healthcheck/", "", -1)),
 	}
 
 	xmlRes := &DeleteHealthCheckResponse{}
@@ -124,3 +143,17 @@ func (r53 *Route53) DeleteHealthCheck(id string) error {
 
 	return nil
 }
+
+// Convenience functions on AWS APIs.
+
+func (c *HealthCheck) Delete() error {
+	return c.r53.DeleteHealthCheck(c.ID)
+}
+
+func (c *HealthCheck) ListTags() (Tags, error) {
+	return c.r53.ListTagsForResource("healthcheck", c.ID)
+}
+
+func (c *HealthCheck) ChangeTags(add Tags, remove []string) error {
+	return c.r53.ChangeTagsForResource("healthcheck", c.ID, add, remove)
+}
@@ -0,0 +1,161 @@
+package route53
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CopyZoneOptions controls CopyZone.
+type CopyZoneOptions struct {
+	// Client for the destination zone, when it lives in another account.
+	// Defaults to the client CopyZone is called on.
+	Destination *Route53
+
+	// Replace destination record sets that differ from the source instead
+	// of skipping them.
+	Overwrite bool
+
+	// Only work out what would be copied.
+	DryRun  bool
+	Comment string
+}
+
+// CopyZoneResult reports what CopyZone did. Conflicts holds the source record
+// sets that differ from an existing destination record set; they are only
+// copied when overwriting.
+type CopyZoneResult struct {
+	Copied    []RRSet
+	Conflicts []RRSet
+	Changes   []ChangeInfo
+}
+
+// CopyZone replicates the record sets of one hosted zone into another,
+// skipping the apex SOA and NS records and the record sets of traffic policy
+// instances, which are never overwritten in the destination either. Aliases
+// to records in the source zone are pointed at the destination zone and
+// created after their targets, and if the zones have different names record
+// names are moved under the destination name. Health check IDs are copied as
+// they are.
+func (r53 *Route53) CopyZone(srcID, dstID string, opts CopyZoneOptions) (CopyZoneResult, error) {
+	dstR53 := opts.Destination
+	if dstR53 == nil {
+		dstR53 = r53
+	}
+
+	src, err := r53.GetHostedZone(srcID)
+	if err != nil {
+		return CopyZoneResult{}, err
+	}
+	dst, err := dstR53.GetHostedZone(dstID)
+	if err != nil {
+		return CopyZoneResult{}, err
+	}
+
+	srcRRSets, err := src.ListRRSets()
+	if err != nil {
+		return CopyZoneResult{}, err
+	}
+	dstRRSets, err := dst.ListRRSets()
+	if err != nil {
+		return CopyZoneResult{}, err
+	}
+
+	existing := map[string]RRSet{}
+	for _, rrset := range dstRRSets {
+		existing[rrsetKey(rrset)] = rrset
+	}
+
+	rename := func(name string) string {
+		if !IsSubdomain(name, src.Name) {
+			return name
+		}
+		return AbsoluteName(RelativeName(name, src.Name), dst.Name)
+	}
+	srcZoneID := strings.Replace(src.ID, "/hostedzone/", "", -1)
+	dstZoneID := strings.Replace(dst.ID, "/hostedzone/", "", -1)
+
+	result := CopyZoneResult{
+		Copied:    []RRSet{},
+		Conflicts: []RRSet{},
+		Changes:   []ChangeInfo{},
+	}
+	changes := []RRSetChange{}
+
+	for _, rrset := range srcRRSets {
+		if isZoneApexRecord(rrset, src.Name) || isTrafficPolicyRecord(rrset) {
+			continue
+		}
+
+		rrset.Name = rename(rrset.Name)
+		if rrset.AliasTarget != nil && strings.Replace(rrset.AliasTarget.HostedZoneID, "/hostedzone/", "", -1) == srcZoneID {
+			alias := *rrset.AliasTarget
+			alias.HostedZoneID = dstZoneID
+			alias.DNSName = rename(alias.DNSName)
+			rrset.AliasTarget = &alias
+		}
+
+		action := "CREATE"
+		if current, ok := existing[rrsetKey(rrset)]; ok {
+			if reflect.DeepEqual(current, rrset) {
+				continue
+			}
+			result.Conflicts = append(result.Conflicts, rrset)
+			if !opts.Overwrite || isTrafficPolicyRecord(current) {
+				continue
+			}
+			action = "UPSERT"
+		}
+
+		result.Copied = append(result.Copied, rrset)
+		changes = append(changes, RRSetChange{Action: action, RRSet: rrset})
+	}
+
+	if opts.DryRun || len(changes) == 0 {
+		return result, nil
+	}
+
+	result.Changes, err = dst.changeRRSetBatches(orderAliasChanges(changes, dstZoneID), opts.Comment, dstRRSets)
+
+	return result, err
+}
+
+// orderAliasChanges moves changes to aliases that point into the zone after
+// all other changes, each after any alias it points at, so that no alias is
+// created before its target.
+func orderAliasChanges(changes []RRSetChange, zoneID string) []RRSetChange {
+	ordered := []RRSetChange{}
+	aliases := []RRSetChange{}
+	for _, change := range changes {
+		alias := change.RRSet.AliasTarget
+		if alias != nil && strings.Replace(alias.HostedZoneID, "/hostedzone/", "", -1) == zoneID {
+			aliases = append(aliases, change)
+		} else {
+			ordered = append(ordered, change)
+		}
+	}
+
+	for len(aliases) > 0 {
+		waiting := map[string]bool{}
+		for _, change := range aliases {
+			waiting[CanonicalName(change.RRSet.Name)] = true
+		}
+
+		next := []RRSetChange{}
+		for _, change := range aliases {
+			if waiting[CanonicalName(change.RRSet.AliasTarget.DNSName)] {
+				next = append(next, change)
+			} else {
+				ordered = append(ordered, change)
+			}
+		}
+
+		// Aliases pointing at each other can't be ordered; leave that
+		// for Route53 to reject.
+		if len(next) == len(aliases) {
+			return append(ordered, next...)
+		}
+		aliases = next
+	}
+
+	return ordered
+}
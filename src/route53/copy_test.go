@@ -0,0 +1,60 @@
+package route53
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCopyZoneCreatesAliasesAfterTargets(t *testing.T) {
+	rrset := func(name, rest string) string {
+		return `<ResourceRecordSet><Name>` + name + `</Name>` + rest + `</ResourceRecordSet>`
+	}
+	alias := func(name, target string) string {
+		return rrset(name, `<Type>A</Type><AliasTarget><HostedZoneId>/hostedzone/Z1</HostedZoneId><DNSName>`+
+			target+`</DNSName><EvaluateTargetHealth>false</EvaluateTargetHealth></AliasTarget>`)
+	}
+	src := `<ListResourceRecordSetsResponse><ResourceRecordSets>` +
+		alias("a.example.com.", "b.example.com.") +
+		alias("b.example.com.", "z.example.com.") +
+		rrset("m.example.com.", `<Type>A</Type><AliasTarget><HostedZoneId>Z2FDTNDATAQYW2</HostedZoneId><DNSName>d1.cloudfront.net.</DNSName><EvaluateTargetHealth>false</EvaluateTargetHealth></AliasTarget>`) +
+		rrset("z.example.com.", `<Type>A</Type><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord></ResourceRecords>`) +
+		`</ResourceRecordSets><IsTruncated>false</IsTruncated></ListResourceRecordSetsResponse>`
+
+	r53, requests := recordingServer(t, map[string]string{
+		"GET /2013-04-01/hostedzone/Z1":        `<GetHostedZoneResponse><HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name></HostedZone></GetHostedZoneResponse>`,
+		"GET /2013-04-01/hostedzone/Z2":        `<GetHostedZoneResponse><HostedZone><Id>/hostedzone/Z2</Id><Name>example.com.</Name></HostedZone></GetHostedZoneResponse>`,
+		"GET /2013-04-01/hostedzone/Z1/rrset":  src,
+		"GET /2013-04-01/hostedzone/Z2/rrset":  `<ListResourceRecordSetsResponse><ResourceRecordSets/><IsTruncated>false</IsTruncated></ListResourceRecordSetsResponse>`,
+		"POST /2013-04-01/hostedzone/Z2/rrset": `<ChangeResourceRecordSetsResponse>` + testChangeInfo + `</ChangeResourceRecordSetsResponse>`,
+	})
+
+	if _, err := r53.CopyZone("Z1", "Z2", CopyZoneOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	body := changeBodies(*requests)
+	order := []int{}
+	for _, name := range []string{"<Name>m.", "<Name>z.", "<Name>b.", "<Name>a."} {
+		order = append(order, strings.Index(body, name))
+	}
+	for i := 1; i < len(order); i++ {
+		if order[i-1] < 0 || order[i] < order[i-1] {
+			t.Fatalf("changes out of order: %s", body)
+		}
+	}
+	if !strings.Contains(body, "<HostedZoneId>Z2</HostedZoneId>") {
+		t.Errorf("aliases not moved to the destination zone: %s", body)
+	}
+}
+
+func TestOrderAliasChangesCycle(t *testing.T) {
+	alias := func(name, target string) RRSetChange {
+		return RRSetChange{Action: "CREATE", RRSet: RRSet{Name: name, Type: "A",
+			AliasTarget: &AliasTarget{HostedZoneID: "Z1", DNSName: target}}}
+	}
+	changes := []RRSetChange{alias("a.example.com.", "b.example.com."), alias("b.example.com.", "a.example.com.")}
+
+	if ordered := orderAliasChanges(changes, "Z1"); len(ordered) != 2 {
+		t.Errorf("changes lost: %+v", ordered)
+	}
+}
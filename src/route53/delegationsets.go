@@ -0,0 +1,133 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// XML RPC types.
+
+type DelegationSet struct {
+	ID              string `xml:"Id"`
+	CallerReference string
+	NameServers     []string `xml:"NameServers>NameServer"`
+}
+
+type CreateReusableDelegationSetRequest struct {
+	XMLName         xml.Name `xml:"CreateReusableDelegationSetRequest"`
+	XMLNS           string   `xml:"xmlns,attr"`
+	CallerReference string
+	HostedZoneID    string `xml:"HostedZoneId,omitempty"`
+}
+
+type CreateReusableDelegationSetResponse struct {
+	XMLName       xml.Name `xml:"CreateReusableDelegationSetResponse"`
+	DelegationSet DelegationSet
+}
+
+type GetReusableDelegationSetResponse struct {
+	XMLName       xml.Name `xml:"GetReusableDelegationSetResponse"`
+	DelegationSet DelegationSet
+}
+
+type ListReusableDelegationSetsResponse struct {
+	XMLName        xml.Name        `xml:"ListReusableDelegationSetsResponse"`
+	DelegationSets []DelegationSet `xml:"DelegationSets>DelegationSet"`
+	IsTruncated    bool
+	Marker         string
+	NextMarker     string
+	MaxItems       uint
+}
+
+type DeleteReusableDelegationSetResponse struct {
+	XMLName xml.Name `xml:"DeleteReusableDelegationSetResponse"`
+}
+
+// Route53 API requests.
+
+// CreateReusableDelegationSet allocates a set of name servers that can be
+// shared by several hosted zones. If zoneID is not empty the name servers of
+// that zone are reused.
+func (r53 *Route53) CreateReusableDelegationSet(reference, zoneID string) (DelegationSet, error) {
+	xmlReq := &CreateReusableDelegationSetRequest{
+		XMLNS:           "https://route53.amazonaws.com/doc/2013-04-01/",
+		CallerReference: reference,
+		HostedZoneID:    strings.Replace(zoneID, "/hostedzone/", "", -1),
+	}
+
+	req := request{
+		method: "POST",
+		path:   "/2013-04-01/delegationset",
+		body:   xmlReq,
+	}
+
+	xmlRes := &CreateReusableDelegationSetResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return DelegationSet{}, err
+	}
+
+	return xmlRes.DelegationSet, nil
+}
+
+func (r53 *Route53) GetReusableDelegationSet(id string) (DelegationSet, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/delegationset/%s", strings.Replace(id, "/delegationset/", "", -1)),
+	}
+
+	xmlRes := &GetReusableDelegationSetResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return DelegationSet{}, err
+	}
+
+	return xmlRes.DelegationSet, nil
+}
+
+func (r53 *Route53) ListReusableDelegationSets() ([]DelegationSet, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/delegationset",
+	}
+
+	xmlRes := &ListReusableDelegationSetsResponse{}
+
+	sets := []DelegationSet{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return []DelegationSet{}, err
+	}
+	sets = append(sets, xmlRes.DelegationSets...)
+
+	for xmlRes.IsTruncated {
+		req.params = &url.Values{
+			"marker": []string{xmlRes.NextMarker},
+		}
+
+		xmlRes = &ListReusableDelegationSetsResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []DelegationSet{}, err
+		}
+		sets = append(sets, xmlRes.DelegationSets...)
+	}
+
+	return sets, nil
+}
+
+func (r53 *Route53) DeleteReusableDelegationSet(id string) error {
+	req := request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/2013-04-01/delegationset/%s", strings.Replace(id, "/delegationset/", "", -1)),
+	}
+
+	xmlRes := &DeleteReusableDelegationSetResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package route53
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ExportZoneFile writes all record sets of the zone to w in RFC 1035 master
+// file format.
+func (z *HostedZone) ExportZoneFile(w io.Writer) error {
+	rrsets, err := z.ListRRSets()
+	if err != nil {
+		return err
+	}
+
+	return WriteZoneFile(w, z.Name, rrsets)
+}
+
+// WriteZoneFile writes rrsets to w in RFC 1035 master file format with names
+// relative to origin. Alias records and records using weighted, failover,
+// latency, geolocation or multivalue answer routing have no BIND equivalent;
+// they are written commented out, preceded by a comment describing how
+// Route53 serves them.
+func WriteZoneFile(w io.Writer, origin string, rrsets []RRSet) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "$ORIGIN %s\n", bindName(CanonicalName(origin)))
+
+	for _, rrset := range rrsets {
+		name := RelativeName(rrset.Name, origin)
+		if name != "@" {
+			name = bindName(name)
+		}
+
+		policy := routingComment(rrset)
+		if policy != "" {
+			fmt.Fprintf(bw, "; %s\n", policy)
+		}
+
+		prefix := ""
+		if policy != "" || rrset.AliasTarget != nil {
+			prefix = "; "
+		}
+
+		if rrset.AliasTarget != nil {
+			fmt.Fprintf(bw, "%s%s\tIN\t%s\t%s\n", prefix, name, rrset.Type, rrset.AliasTarget.DNSName)
+			continue
+		}
+		if rrset.ResourceRecords == nil {
+			continue
+		}
+		for _, rr := range rrset.ResourceRecords.ResourceRecord {
+			fmt.Fprintf(bw, "%s%s\t%d\tIN\t%s\t%s\n", prefix, name, rrset.TTL, rrset.Type, bindValue(rrset.Type, rr.Value))
+		}
+	}
+
+	return bw.Flush()
+}
+
+func routingComment(rrset RRSet) string {
+	parts := []string{}
+
+	if rrset.AliasTarget != nil {
+		alias := fmt.Sprintf("alias to %s in zone %s", rrset.AliasTarget.DNSName, rrset.AliasTarget.HostedZoneID)
+		if rrset.AliasTarget.EvaluateTargetHealth {
+			alias += " evaluating target health"
+		}
+		parts = append(parts, alias)
+	}
+
+	if rrset.SetIdentifier != "" {
+		switch {
+		case rrset.Failover != "":
+			parts = append(parts, "failover "+rrset.Failover)
+		case rrset.Region != "":
+			parts = append(parts, "latency region "+rrset.Region)
+		case rrset.MultiValueAnswer:
+			parts = append(parts, "multivalue answer")
+		case rrset.GeoLocation != nil:
+			parts = append(parts, "geolocation "+geoLocationString(*rrset.GeoLocation))
+		default:
+			if rrset.Weight != nil {
+				parts = append(parts, fmt.Sprintf("weighted weight %d", *rrset.Weight))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("set %q", rrset.SetIdentifier))
+	}
+
+	if rrset.HealthCheckID != "" {
+		parts = append(parts, "health check "+rrset.HealthCheckID)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func geoLocationString(location GeoLocation) string {
+	switch {
+	case location.ContinentCode != "":
+		return "continent " + location.ContinentCode
+	case location.CountryCode == "*":
+		return "default"
+	case location.SubdivisionCode != "":
+		return "country " + location.CountryCode + " subdivision " + location.SubdivisionCode
+	}
+	return "country " + location.CountryCode
+}
+
+// bindName escapes the characters of a decoded name that are special in a
+// master file, using the RFC 1035 \DDD (decimal) form. Escapes left in the
+// name by DecodeName are converted from octal.
+func bindName(name string) string {
+	escaped := ""
+	for i := 0; i < len(name); i++ {
+		if c, ok := octalEscape(name, i); ok {
+			escaped += fmt.Sprintf("\\%03d", c)
+			i += 3
+			continue
+		}
+
+		c := name[i]
+		switch {
+		case c == '\\' || c == '"' || c == ';' || c == '(' || c == ')' || c == '@' || c == '$':
+			escaped += "\\" + string(c)
+		case c <= ' ' || c >= 0x7f:
+			escaped += fmt.Sprintf("\\%03d", c)
+		default:
+			escaped += string(c)
+		}
+	}
+	return escaped
+}
+
+// bindValue makes sure TXT and SPF values are quoted; Route53 normally
+// returns them quoted already.
+func bindValue(rrtype, value string) string {
+	if (rrtype == "TXT" || rrtype == "SPF") && !strings.HasPrefix(value, "\"") {
+		return strconv.Quote(value)
+	}
+	return value
+}
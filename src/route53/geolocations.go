@@ -0,0 +1,98 @@
+package route53
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// XML RPC types.
+
+type GeoLocationDetails struct {
+	ContinentCode   string
+	ContinentName   string
+	CountryCode     string
+	CountryName     string
+	SubdivisionCode string
+	SubdivisionName string
+}
+
+type GetGeoLocationResponse struct {
+	XMLName            xml.Name `xml:"GetGeoLocationResponse"`
+	GeoLocationDetails GeoLocationDetails
+}
+
+type ListGeoLocationsResponse struct {
+	XMLName             xml.Name             `xml:"ListGeoLocationsResponse"`
+	GeoLocations        []GeoLocationDetails `xml:"GeoLocationDetailsList>GeoLocationDetails"`
+	IsTruncated         bool
+	NextContinentCode   string
+	NextCountryCode     string
+	NextSubdivisionCode string
+	MaxItems            uint
+}
+
+// Route53 API requests.
+
+// ListGeoLocations returns every continent, country and subdivision that can
+// be used in a record set's GeoLocation.
+func (r53 *Route53) ListGeoLocations() ([]GeoLocationDetails, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/geolocations",
+	}
+
+	xmlRes := &ListGeoLocationsResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return []GeoLocationDetails{}, err
+	}
+	locations := xmlRes.GeoLocations
+
+	for xmlRes.IsTruncated {
+		req.params = &url.Values{}
+		if xmlRes.NextContinentCode != "" {
+			req.params.Set("startcontinentcode", xmlRes.NextContinentCode)
+		}
+		if xmlRes.NextCountryCode != "" {
+			req.params.Set("startcountrycode", xmlRes.NextCountryCode)
+		}
+		if xmlRes.NextSubdivisionCode != "" {
+			req.params.Set("startsubdivisioncode", xmlRes.NextSubdivisionCode)
+		}
+
+		xmlRes = &ListGeoLocationsResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []GeoLocationDetails{}, err
+		}
+		locations = append(locations, xmlRes.GeoLocations...)
+	}
+
+	return locations, nil
+}
+
+// GetGeoLocation looks up the names of a location, which also tells whether
+// Route53 supports it.
+func (r53 *Route53) GetGeoLocation(location GeoLocation) (GeoLocationDetails, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/geolocation",
+		params: &url.Values{},
+	}
+	if location.ContinentCode != "" {
+		req.params.Set("continentcode", location.ContinentCode)
+	}
+	if location.CountryCode != "" {
+		req.params.Set("countrycode", location.CountryCode)
+	}
+	if location.SubdivisionCode != "" {
+		req.params.Set("subdivisioncode", location.SubdivisionCode)
+	}
+
+	xmlRes := &GetGeoLocationResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return GeoLocationDetails{}, err
+	}
+
+	return xmlRes.GeoLocationDetails, nil
+}
@@ -0,0 +1,132 @@
+package route53
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testGeoLocations = []GeoLocationDetails{
+	{ContinentCode: "AF", ContinentName: "Africa"},
+	{ContinentCode: "EU", ContinentName: "Europe"},
+	{ContinentCode: "NA", ContinentName: "North America"},
+	{CountryCode: "*", CountryName: "Default"},
+	{CountryCode: "DE", CountryName: "Germany"},
+	{CountryCode: "US", CountryName: "United States"},
+	{CountryCode: "US", CountryName: "United States", SubdivisionCode: "CA", SubdivisionName: "California"},
+	{CountryCode: "US", CountryName: "United States", SubdivisionCode: "NY", SubdivisionName: "New York"},
+}
+
+// geoServer answers ListGeoLocations from testGeoLocations two at a time and
+// GetGeoLocation for any of them, returning the query of every request.
+func geoServer(t *testing.T) (*Route53, *[]string) {
+	queries := []string{}
+	r53 := handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		queries = append(queries, r.URL.Path+"?"+query.Encode())
+
+		switch r.URL.Path {
+		case "/2013-04-01/geolocations":
+			start := 0
+			if query.Encode() != "" {
+				start = len(testGeoLocations)
+				for i, location := range testGeoLocations {
+					if location.ContinentCode == query.Get("startcontinentcode") && location.CountryCode == query.Get("startcountrycode") &&
+						location.SubdivisionCode == query.Get("startsubdivisioncode") {
+						start = i
+						break
+					}
+				}
+			}
+
+			res := ListGeoLocationsResponse{GeoLocations: testGeoLocations[start:], MaxItems: 2}
+			if len(res.GeoLocations) > 2 {
+				next := res.GeoLocations[2]
+				res.GeoLocations = res.GeoLocations[:2]
+				res.IsTruncated = true
+				res.NextContinentCode, res.NextCountryCode, res.NextSubdivisionCode = next.ContinentCode, next.CountryCode, next.SubdivisionCode
+			}
+			data, _ := xml.Marshal(res)
+			w.Write(data)
+
+		case "/2013-04-01/geolocation":
+			for _, location := range testGeoLocations {
+				if location.ContinentCode == query.Get("continentcode") && location.CountryCode == query.Get("countrycode") &&
+					location.SubdivisionCode == query.Get("subdivisioncode") {
+					data, _ := xml.Marshal(GetGeoLocationResponse{GeoLocationDetails: location})
+					w.Write(data)
+					return
+				}
+			}
+			w.WriteHeader(404)
+			w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchGeoLocation</Code><Message>not found</Message></Error></ErrorResponse>`))
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+
+	return r53, &queries
+}
+
+func TestListGeoLocations(t *testing.T) {
+	r53, queries := geoServer(t)
+
+	locations, err := r53.ListGeoLocations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(locations, testGeoLocations) {
+		t.Errorf("got %+v, want %+v", locations, testGeoLocations)
+	}
+
+	// Pages start at a continent, at countries and at a subdivision.
+	want := []string{
+		"/2013-04-01/geolocations?",
+		"/2013-04-01/geolocations?startcontinentcode=NA",
+		"/2013-04-01/geolocations?startcountrycode=DE",
+		"/2013-04-01/geolocations?startcountrycode=US&startsubdivisioncode=CA",
+	}
+	if !reflect.DeepEqual(*queries, want) {
+		t.Errorf("queries %v, want %v", *queries, want)
+	}
+}
+
+func TestGetGeoLocation(t *testing.T) {
+	r53, queries := geoServer(t)
+
+	tests := []struct {
+		location GeoLocation
+		name     string
+	}{
+		{GeoLocation{ContinentCode: "EU"}, "Europe"},
+		{GeoLocation{CountryCode: "DE"}, "Germany"},
+		{GeoLocation{CountryCode: "US", SubdivisionCode: "CA"}, "California"},
+	}
+	for _, test := range tests {
+		details, err := r53.GetGeoLocation(test.location)
+		if err != nil {
+			t.Errorf("%+v: %s", test.location, err)
+			continue
+		}
+		name := details.ContinentName
+		if details.SubdivisionName != "" {
+			name = details.SubdivisionName
+		} else if details.CountryName != "" {
+			name = details.CountryName
+		}
+		if name != test.name {
+			t.Errorf("%+v: got %+v", test.location, details)
+		}
+	}
+	if (*queries)[2] != "/2013-04-01/geolocation?countrycode=US&subdivisioncode=CA" {
+		t.Errorf("unexpected query %s", (*queries)[2])
+	}
+
+	_, err := r53.GetGeoLocation(GeoLocation{CountryCode: "XX"})
+	if e, ok := err.(*Error); !ok || e.Code != "NoSuchGeoLocation" {
+		t.Errorf("unknown location: unexpected error %#v", err)
+	}
+}
@@ -0,0 +1,543 @@
+package route53
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ImportZoneOptions controls ImportZoneFile.
+type ImportZoneOptions struct {
+	// Replace existing record sets (UPSERT) instead of failing on them.
+	Overwrite bool
+
+	// Only work out the changes, don't submit them.
+	DryRun  bool
+	Comment string
+
+	// Directory that relative $INCLUDE paths in the file are resolved
+	// against, usually the one the file is in. Defaults to the working
+	// directory.
+	IncludeDir string
+}
+
+// ImportZoneFile parses an RFC 1035 master file relative to the zone name and
+// creates its record sets in the zone in batches. The apex SOA and NS records
+// are skipped since Route53 manages those, and when overwriting so are names
+// and types whose record sets belong to a traffic policy instance. It returns
+// the changes that were (or, in a dry run, would be) submitted.
+func (z *HostedZone) ImportZoneFile(r io.Reader, opts ImportZoneOptions) ([]RRSetChange, []ChangeInfo, error) {
+	rrsets, err := parseZoneFile(r, z.Name, opts.IncludeDir)
+	if err != nil {
+		return []RRSetChange{}, []ChangeInfo{}, err
+	}
+
+	action := "CREATE"
+	policyRecords := map[string]bool{}
+	var current []RRSet
+	if opts.Overwrite {
+		action = "UPSERT"
+
+		if current, err = z.ListRRSets(); err != nil {
+			return []RRSetChange{}, []ChangeInfo{}, err
+		}
+		for _, rrset := range current {
+			if isTrafficPolicyRecord(rrset) {
+				policyRecords[CanonicalName(rrset.Name)+" "+rrset.Type] = true
+			}
+		}
+	}
+
+	changes := []RRSetChange{}
+	for _, rrset := range rrsets {
+		if isZoneApexRecord(rrset, z.Name) || policyRecords[CanonicalName(rrset.Name)+" "+rrset.Type] {
+			continue
+		}
+		changes = append(changes, RRSetChange{Action: action, RRSet: rrset})
+	}
+
+	if opts.DryRun {
+		return changes, []ChangeInfo{}, nil
+	}
+
+	infos, err := z.changeRRSetBatches(changes, opts.Comment, current)
+
+	return changes, infos, err
+}
+
+// ParseZoneFile parses an RFC 1035 master file into record sets, grouping
+// records by name and type. Relative names are resolved against origin.
+// $ORIGIN, $TTL and $INCLUDE are supported; relative paths are opened
+// relative to the working directory, or to the including file for nested
+// includes.
+func ParseZoneFile(r io.Reader, origin string) ([]RRSet, error) {
+	return parseZoneFile(r, origin, "")
+}
+
+func parseZoneFile(r io.Reader, origin, dir string) ([]RRSet, error) {
+	p := &zoneParser{
+		dir:   dir,
+		index: map[string]int{},
+	}
+
+	var err error
+	if p.origin, err = p.absName(origin); err != nil {
+		return []RRSet{}, err
+	}
+
+	if err := p.parse(r); err != nil {
+		return []RRSet{}, err
+	}
+
+	return p.rrsets, nil
+}
+
+// Maximum depth of nested $INCLUDE directives.
+const maxZoneIncludes = 10
+
+type zoneParser struct {
+	origin    string
+	lastOwner string
+	includes  int
+
+	// Directory of the file being parsed, for relative $INCLUDE paths.
+	dir string
+
+	defaultTTL     uint
+	haveDefaultTTL bool
+	lastTTL        uint
+	haveLastTTL    bool
+
+	rrsets []RRSet
+	index  map[string]int
+}
+
+func (p *zoneParser) parse(r io.Reader) error {
+	lines, err := lexZoneFile(r)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if err := p.parseLine(line); err != nil {
+			return fmt.Errorf("line %d: %s", line.number, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *zoneParser) parseLine(line zoneLine) error {
+	tokens := line.tokens
+
+	if first := tokens[0]; !first.quoted && strings.HasPrefix(first.text, "$") {
+		return p.parseDirective(strings.ToUpper(first.text), tokens[1:])
+	}
+
+	owner := p.lastOwner
+	if !line.blankOwner {
+		var err error
+		if owner, err = p.absName(tokens[0].text); err != nil {
+			return err
+		}
+		tokens = tokens[1:]
+	}
+	if owner == "" {
+		return errors.New("record has no owner name")
+	}
+	p.lastOwner = owner
+
+	// TTL and class may come in either order before the type.
+	var ttl uint
+	haveTTL, haveClass := false, false
+	for len(tokens) > 0 {
+		if !haveTTL {
+			if v, err := parseTTL(tokens[0].text); err == nil {
+				ttl, haveTTL = v, true
+				tokens = tokens[1:]
+				continue
+			}
+		}
+		if !haveClass && isDNSClass(tokens[0].text) {
+			if !strings.EqualFold(tokens[0].text, "IN") {
+				return fmt.Errorf("unsupported class %s", tokens[0].text)
+			}
+			haveClass = true
+			tokens = tokens[1:]
+			continue
+		}
+		break
+	}
+
+	if len(tokens) < 2 {
+		return errors.New("record needs a type and data")
+	}
+	rrtype := strings.ToUpper(tokens[0].text)
+
+	switch {
+	case haveTTL:
+		p.lastTTL, p.haveLastTTL = ttl, true
+	case p.haveDefaultTTL:
+		ttl = p.defaultTTL
+	case p.haveLastTTL:
+		ttl = p.lastTTL
+	default:
+		return errors.New("record has no TTL and there is no $TTL")
+	}
+
+	value, err := p.rdata(rrtype, tokens[1:])
+	if err != nil {
+		return err
+	}
+
+	p.add(owner, rrtype, ttl, value)
+
+	return nil
+}
+
+func (p *zoneParser) parseDirective(directive string, args []zoneToken) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s needs an argument", directive)
+	}
+
+	switch directive {
+	case "$ORIGIN":
+		origin, err := p.absName(args[0].text)
+		if err != nil {
+			return err
+		}
+		p.origin = origin
+	case "$TTL":
+		ttl, err := parseTTL(args[0].text)
+		if err != nil {
+			return err
+		}
+		p.defaultTTL, p.haveDefaultTTL = ttl, true
+	case "$INCLUDE":
+		if p.includes >= maxZoneIncludes {
+			return errors.New("$INCLUDE nested too deeply")
+		}
+
+		path := args[0].text
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(p.dir, path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// The included file may have its own origin and resolves its own
+		// includes from where it is; ours are restored after.
+		origin, owner, dir := p.origin, p.lastOwner, p.dir
+		if len(args) > 1 {
+			if p.origin, err = p.absName(args[1].text); err != nil {
+				return err
+			}
+		}
+		p.dir = filepath.Dir(path)
+		p.includes++
+		err = p.parse(f)
+		p.includes--
+		p.origin, p.lastOwner, p.dir = origin, owner, dir
+
+		if err != nil {
+			return fmt.Errorf("%s: %s", args[0].text, err)
+		}
+	default:
+		return fmt.Errorf("unsupported directive %s", directive)
+	}
+
+	return nil
+}
+
+// rdata turns the data fields of a record into a Route53 value, making
+// embedded domain names absolute.
+func (p *zoneParser) rdata(rrtype string, tokens []zoneToken) (string, error) {
+	fields := make([]string, len(tokens))
+	for i, token := range tokens {
+		fields[i] = token.text
+	}
+
+	need := func(n int) error {
+		if len(fields) < n {
+			return fmt.Errorf("%s record needs %d fields", rrtype, n)
+		}
+		return nil
+	}
+
+	// Fields holding domain names.
+	names := []int{}
+
+	switch rrtype {
+	case "TXT", "SPF":
+		for i, token := range tokens {
+			if !token.quoted {
+				fields[i] = "\"" + token.text + "\""
+			}
+		}
+	case "NS", "CNAME", "PTR":
+		names = []int{0}
+	case "MX":
+		if err := need(2); err != nil {
+			return "", err
+		}
+		names = []int{1}
+	case "SRV":
+		if err := need(4); err != nil {
+			return "", err
+		}
+		names = []int{3}
+	case "NAPTR":
+		if err := need(6); err != nil {
+			return "", err
+		}
+		names = []int{5}
+	case "SOA":
+		if err := need(7); err != nil {
+			return "", err
+		}
+		names = []int{0, 1}
+		for i := 2; i < 7; i++ {
+			n, err := parseTTL(fields[i])
+			if err != nil {
+				return "", err
+			}
+			fields[i] = strconv.FormatUint(uint64(n), 10)
+		}
+	}
+
+	for _, i := range names {
+		name, err := p.absName(fields[i])
+		if err != nil {
+			return "", err
+		}
+		fields[i] = name
+	}
+
+	return strings.Join(fields, " "), nil
+}
+
+func (p *zoneParser) add(name, rrtype string, ttl uint, value string) {
+	rrset := RRSet{Name: name, Type: rrtype}
+	key := rrsetKey(rrset)
+
+	i, ok := p.index[key]
+	if !ok {
+		rrset.TTL = ttl
+		rrset.ResourceRecords = &ResourceRecords{}
+		p.rrsets = append(p.rrsets, rrset)
+		i = len(p.rrsets) - 1
+		p.index[key] = i
+	}
+
+	// Route53 has a single TTL per record set; use the lowest.
+	if ttl < p.rrsets[i].TTL {
+		p.rrsets[i].TTL = ttl
+	}
+	records := p.rrsets[i].ResourceRecords
+	records.ResourceRecord = append(records.ResourceRecord, ResourceRecord{Value: value})
+}
+
+// absName resolves a master file name against the current origin and
+// converts its escapes to the form Route53 uses.
+func (p *zoneParser) absName(name string) (string, error) {
+	if name == "@" {
+		return p.origin, nil
+	}
+	if !strings.HasSuffix(name, ".") || strings.HasSuffix(name, "\\.") {
+		name += "." + p.origin
+	}
+	return route53NameFromBind(name)
+}
+
+// route53NameFromBind converts the \DDD (decimal) and \X escapes of a master
+// file name into Route53's \NNN (octal) escapes, lower casing it on the way.
+func route53NameFromBind(name string) (string, error) {
+	encoded := ""
+	for i := 0; i < len(name); i++ {
+		c, escaped := name[i], false
+		if c == '\\' && i+1 < len(name) {
+			escaped = true
+			if i+3 < len(name) && isDigit(name[i+1]) && isDigit(name[i+2]) && isDigit(name[i+3]) {
+				n, _ := strconv.Atoi(name[i+1 : i+4])
+				if n > 255 {
+					return "", fmt.Errorf("escape \\%s in %q is out of range", name[i+1:i+4], name)
+				}
+				c = byte(n)
+				i += 3
+			} else {
+				c = name[i+1]
+				i++
+			}
+		}
+
+		switch {
+		case c >= 'A' && c <= 'Z':
+			encoded += string(c + 'a' - 'A')
+		case c >= 'a' && c <= 'z', isDigit(c), c == '-', c == '_', c == '*', c == '.' && !escaped:
+			encoded += string(c)
+		default:
+			encoded += fmt.Sprintf("\\%03o", c)
+		}
+	}
+	return encoded, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isDNSClass(s string) bool {
+	switch strings.ToUpper(s) {
+	case "IN", "CH", "CS", "HS":
+		return true
+	}
+	return false
+}
+
+// parseTTL parses a TTL given in seconds or with BIND unit suffixes, e.g. 1h30m.
+func parseTTL(s string) (uint, error) {
+	if s == "" || !isDigit(s[0]) {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+
+	var total, n uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isDigit(c) {
+			n = n*10 + uint64(c-'0')
+			continue
+		}
+		if i == 0 || !isDigit(s[i-1]) {
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+
+		switch c {
+		case 's', 'S':
+			total += n
+		case 'm', 'M':
+			total += n * 60
+		case 'h', 'H':
+			total += n * 60 * 60
+		case 'd', 'D':
+			total += n * 60 * 60 * 24
+		case 'w', 'W':
+			total += n * 60 * 60 * 24 * 7
+		default:
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+		n = 0
+	}
+	total += n
+
+	if total > 1<<31-1 {
+		return 0, fmt.Errorf("TTL %q out of range", s)
+	}
+
+	return uint(total), nil
+}
+
+// Master file lexing.
+
+type zoneToken struct {
+	text   string
+	quoted bool
+}
+
+type zoneLine struct {
+	number     int
+	tokens     []zoneToken
+	blankOwner bool
+}
+
+// lexZoneFile splits a master file into logical lines of tokens, dropping
+// comments and joining lines continued in parentheses. Quoted strings keep
+// their quotes and escapes.
+func lexZoneFile(r io.Reader) ([]zoneLine, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return []zoneLine{}, err
+	}
+
+	lines := []zoneLine{}
+	number := 1
+	line := zoneLine{number: number}
+	parens := 0
+	lineStart := true
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		start := lineStart
+		lineStart = false
+
+		switch {
+		case c == '\n':
+			number++
+			if parens == 0 {
+				if len(line.tokens) > 0 {
+					lines = append(lines, line)
+				}
+				line = zoneLine{number: number}
+				lineStart = true
+			}
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			if start {
+				line.blankOwner = true
+			}
+			i++
+		case c == ';':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '(':
+			parens++
+			i++
+		case c == ')':
+			if parens == 0 {
+				return []zoneLine{}, fmt.Errorf("line %d: unbalanced )", number)
+			}
+			parens--
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(data) && data[j] != '"'; j++ {
+				if data[j] == '\\' {
+					j++
+				} else if data[j] == '\n' {
+					number++
+				}
+			}
+			if j >= len(data) {
+				return []zoneLine{}, fmt.Errorf("line %d: unterminated string", number)
+			}
+			line.tokens = append(line.tokens, zoneToken{text: string(data[i : j+1]), quoted: true})
+			i = j + 1
+		default:
+			j := i
+			for ; j < len(data) && !strings.ContainsRune(" \t\r\n;()\"", rune(data[j])); j++ {
+				if data[j] == '\\' && j+1 < len(data) {
+					j++
+				}
+			}
+			line.tokens = append(line.tokens, zoneToken{text: string(data[i:j])})
+			i = j
+		}
+	}
+
+	if parens != 0 {
+		return []zoneLine{}, fmt.Errorf("line %d: unbalanced (", number)
+	}
+	if len(line.tokens) > 0 {
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
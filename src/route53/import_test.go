@@ -0,0 +1,226 @@
+package route53
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLexZoneFile(t *testing.T) {
+	tests := []struct {
+		input      string
+		tokens     [][]string
+		blankOwner []bool
+	}{
+		{"www 60 IN A 192.0.2.1\n", [][]string{{"www", "60", "IN", "A", "192.0.2.1"}}, []bool{false}},
+		{"www A 192.0.2.1 ; comment\n  A 192.0.2.2\n", [][]string{{"www", "A", "192.0.2.1"}, {"A", "192.0.2.2"}}, []bool{false, true}},
+		{"@ SOA ns hostmaster (\n  1 ; serial\n  2 3 4 5 )\nwww A 192.0.2.1\n",
+			[][]string{{"@", "SOA", "ns", "hostmaster", "1", "2", "3", "4", "5"}, {"www", "A", "192.0.2.1"}}, []bool{false, false}},
+		{`txt TXT "a b;c" "say \"hi\"" unquoted`,
+			[][]string{{"txt", "TXT", `"a b;c"`, `"say \"hi\""`, "unquoted"}}, []bool{false}},
+		{"txt TXT \"spans (\nlines)\"\n", [][]string{{"txt", "TXT", "\"spans (\nlines)\""}}, []bool{false}},
+		{`a\ b A 192.0.2.1`, [][]string{{`a\ b`, "A", "192.0.2.1"}}, []bool{false}},
+		{"\n; only a comment\n\n", [][]string{}, []bool{}},
+	}
+
+	for _, test := range tests {
+		lines, err := lexZoneFile(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("%q: %s", test.input, err)
+			continue
+		}
+
+		tokens, blank := [][]string{}, []bool{}
+		for _, line := range lines {
+			texts := []string{}
+			for _, token := range line.tokens {
+				texts = append(texts, token.text)
+			}
+			tokens = append(tokens, texts)
+			blank = append(blank, line.blankOwner)
+		}
+		if !reflect.DeepEqual(tokens, test.tokens) || !reflect.DeepEqual(blank, test.blankOwner) {
+			t.Errorf("%q: got %q %v, want %q %v", test.input, tokens, blank, test.tokens, test.blankOwner)
+		}
+	}
+}
+
+func TestLexZoneFileErrors(t *testing.T) {
+	for _, input := range []string{
+		"www A 192.0.2.1 )\n",
+		"www TXT \"unterminated\n",
+	} {
+		if _, err := lexZoneFile(strings.NewReader(input)); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestRoute53NameFromBind(t *testing.T) {
+	tests := []struct {
+		bind, route53 string
+	}{
+		{"WWW.Example.COM.", "www.example.com."},
+		{"*.example.com.", "*.example.com."},
+		{`a\.b.example.com.`, `a\056b.example.com.`},
+		{`a\046b.example.com.`, `a\056b.example.com.`},
+		{`a\032b.example.com.`, `a\040b.example.com.`},
+		{`\065.example.com.`, "a.example.com."},
+		{`caf\195\169.example.com.`, `caf\303\251.example.com.`},
+		{`a\@b.example.com.`, `a\100b.example.com.`},
+	}
+
+	for _, test := range tests {
+		got, err := route53NameFromBind(test.bind)
+		if err != nil || got != test.route53 {
+			t.Errorf("route53NameFromBind(%q) = %q, %v, want %q", test.bind, got, err, test.route53)
+		}
+	}
+
+	if got, err := route53NameFromBind(`a\256b.example.com.`); err == nil {
+		t.Errorf("route53NameFromBind with \\256 = %q, expected an error", got)
+	}
+}
+
+func TestParseZoneFile(t *testing.T) {
+	zone := `$TTL 1h
+@	IN	SOA	ns1 hostmaster ( 2013040101 1h 15m 1w 5m )
+	IN	NS	ns1
+www	300	IN	A	192.0.2.1
+	IN	300	A	192.0.2.2
+mail	MX	10 mx.example.net.
+$ORIGIN sub.example.com.
+host	CNAME	www.example.com.
+txt	TXT	"v=spf1 -all" more
+`
+
+	rrsets, err := ParseZoneFile(strings.NewReader(zone), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RRSet{
+		{Name: "example.com.", Type: "SOA", TTL: 3600, ResourceRecords: &ResourceRecords{[]ResourceRecord{
+			{"ns1.example.com. hostmaster.example.com. 2013040101 3600 900 604800 300"}}}},
+		{Name: "example.com.", Type: "NS", TTL: 3600, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"ns1.example.com."}}}},
+		{Name: "www.example.com.", Type: "A", TTL: 300, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"192.0.2.1"}, {"192.0.2.2"}}}},
+		{Name: "mail.example.com.", Type: "MX", TTL: 3600, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"10 mx.example.net."}}}},
+		{Name: "host.sub.example.com.", Type: "CNAME", TTL: 3600, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"www.example.com."}}}},
+		{Name: "txt.sub.example.com.", Type: "TXT", TTL: 3600, ResourceRecords: &ResourceRecords{[]ResourceRecord{{`"v=spf1 -all" "more"`}}}},
+	}
+	if !reflect.DeepEqual(rrsets, want) {
+		t.Errorf("got %+v\nwant %+v", rrsets, want)
+	}
+}
+
+func TestParseZoneFileInclude(t *testing.T) {
+	dir, err := ioutil.TempDir("", "route53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	included := filepath.Join(dir, "hosts.zone")
+	if err := ioutil.WriteFile(included, []byte("db 60 A 192.0.2.10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	zone := "$INCLUDE " + included + " internal.example.com.\n" +
+		"$INCLUDE " + included + "\n" +
+		"after 60 A 192.0.2.20\n"
+
+	rrsets, err := ParseZoneFile(strings.NewReader(zone), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, rrset := range rrsets {
+		names = append(names, rrset.Name)
+	}
+	want := []string{"db.internal.example.com.", "db.example.com.", "after.example.com."}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+
+	self := filepath.Join(dir, "self.zone")
+	if err := ioutil.WriteFile(self, []byte("$INCLUDE "+self+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseZoneFile(strings.NewReader("$INCLUDE "+self+"\n"), "example.com."); err == nil {
+		t.Error("recursive $INCLUDE: expected an error")
+	}
+
+	// Relative paths resolve against the including file's directory.
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	nested := "$INCLUDE hosts.zone\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "sub", "nested.zone"), []byte(nested), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "sub", "hosts.zone"), []byte("nested 60 A 192.0.2.30\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rrsets, err = parseZoneFile(strings.NewReader("$INCLUDE sub/nested.zone\n"), "example.com.", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rrsets) != 1 || rrsets[0].Name != "nested.example.com." {
+		t.Errorf("relative $INCLUDE: got %+v", rrsets)
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	for _, zone := range []string{
+		"www A 192.0.2.1\n",
+		"$TTL 60\nwww CH A 192.0.2.1\n",
+		"$TTL 60\nwww A\n",
+		"$TTL 60\nmail MX 10\n",
+		"$TTL 1x\n",
+		"$GENERATE 1-10 host$ A 192.0.2.$\n",
+		"$TTL 60\n A 192.0.2.1\n",
+		"$TTL 60\nwww CNAME a\\300b.example.com.\n",
+	} {
+		if _, err := ParseZoneFile(strings.NewReader(zone), "example.com."); err == nil {
+			t.Errorf("%q: expected an error", zone)
+		}
+	}
+}
+
+func TestZoneFileRoundTrip(t *testing.T) {
+	rrsets := []RRSet{
+		{Name: "example.com.", Type: "MX", TTL: 300, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"10 mail.example.com."}, {"20 mx.example.net."}}}},
+		{Name: "www.example.com.", Type: "A", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"192.0.2.1"}}}},
+		{Name: `\052.example.com.`, Type: "CNAME", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"www.example.com."}}}},
+		{Name: `a\056b.example.com.`, Type: "TXT", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{`"dotted label"`}}}},
+		{Name: `sp\040ace.example.com.`, Type: "TXT", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{`"a;b" "c\"d"`}}}},
+		{Name: `_sip._tcp.example.com.`, Type: "SRV", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{"10 5 5060 sip.example.com."}}}},
+	}
+	skipped := []RRSet{
+		{Name: "api.example.com.", Type: "A", AliasTarget: &AliasTarget{HostedZoneID: "Z1", DNSName: "lb.example.net."}},
+		{Name: "w.example.com.", Type: "A", TTL: 60, SetIdentifier: "one", Weight: weight(10),
+			ResourceRecords: &ResourceRecords{[]ResourceRecord{{"192.0.2.5"}}}},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteZoneFile(buf, "example.com.", append(rrsets, skipped...)); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseZoneFile(buf, "example.com.")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, buf)
+	}
+	// Route53 escapes a leading * as \052 but accepts it either way.
+	for i := range parsed {
+		parsed[i].Name = EncodeName(parsed[i].Name)
+	}
+	if !reflect.DeepEqual(parsed, rrsets) {
+		t.Errorf("got %+v\nwant %+v", parsed, rrsets)
+	}
+}
@@ -0,0 +1,332 @@
+package route53
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeName replaces the \NNN octal escapes in name with the characters
+// they stand for. Escaped dots and backslashes are left escaped so label
+// boundaries survive.
+func DecodeName(name string) string {
+	if !strings.Contains(name, "\\") {
+		return name
+	}
+
+	decoded := []byte{}
+	for i := 0; i < len(name); i++ {
+		if c, ok := octalEscape(name, i); ok {
+			if c == '.' || c == '\\' {
+				decoded = append(decoded, name[i:i+4]...)
+			} else {
+				decoded = append(decoded, c)
+			}
+			i += 3
+			continue
+		}
+		decoded = append(decoded, name[i])
+	}
+	return string(decoded)
+}
+
+// EncodeName lower cases name and escapes every character Route53 would
+// escape, giving the form ListRRSets returns. Existing escapes are kept.
+func EncodeName(name string) string {
+	encoded := []byte{}
+	for i := 0; i < len(name); i++ {
+		if _, ok := octalEscape(name, i); ok {
+			encoded = append(encoded, name[i:i+4]...)
+			i += 3
+			continue
+		}
+
+		switch c := name[i]; {
+		case c >= 'A' && c <= 'Z':
+			encoded = append(encoded, c+'a'-'A')
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+			encoded = append(encoded, c)
+		default:
+			encoded = append(encoded, fmt.Sprintf("\\%03o", c)...)
+		}
+	}
+	return string(encoded)
+}
+
+// CanonicalName returns name lower cased, decoded and with a trailing dot,
+// which is the form names should be compared in.
+func CanonicalName(name string) string {
+	name = strings.ToLower(DecodeName(name))
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+// NamesEqual reports whether two names refer to the same domain.
+func NamesEqual(a, b string) bool {
+	return CanonicalName(a) == CanonicalName(b)
+}
+
+// IsSubdomain reports whether name is zone or a name inside it.
+func IsSubdomain(name, zone string) bool {
+	name, zone = CanonicalName(name), CanonicalName(zone)
+	return name == zone || zone == "." || strings.HasSuffix(name, "."+zone)
+}
+
+// AbsoluteName resolves name against zone: "@" and "" stand for the zone
+// itself, names with a trailing dot are already absolute, and anything else
+// is relative to the zone.
+func AbsoluteName(name, zone string) string {
+	if !strings.HasSuffix(zone, ".") {
+		zone += "."
+	}
+
+	switch {
+	case name == "@" || name == "":
+		return zone
+	case strings.HasSuffix(name, "."):
+		return name
+	}
+	return name + "." + zone
+}
+
+// RelativeName returns name relative to zone, "@" for the zone itself, or the
+// canonical absolute name if it is outside the zone.
+func RelativeName(name, zone string) string {
+	name, zone = CanonicalName(name), CanonicalName(zone)
+
+	switch {
+	case name == zone:
+		return "@"
+	case strings.HasSuffix(name, "."+zone):
+		return name[:len(name)-len(zone)-1]
+	}
+	return name
+}
+
+// ToASCIIName converts the labels of an internationalized name to their
+// punycode (xn--) form. Every label is lower cased but not otherwise
+// normalized.
+func ToASCIIName(name string) (string, error) {
+	labels := strings.Split(DecodeName(name), ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			labels[i] = strings.ToLower(label)
+			continue
+		}
+		if !utf8.ValidString(label) {
+			return "", fmt.Errorf("label %q is not valid UTF-8", label)
+		}
+
+		encoded, err := punycodeEncode(strings.ToLower(label))
+		if err != nil {
+			return "", err
+		}
+		labels[i] = "xn--" + encoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// ToUnicodeName converts the punycode (xn--) labels of a name back to Unicode.
+func ToUnicodeName(name string) (string, error) {
+	labels := strings.Split(DecodeName(name), ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), "xn--") {
+			continue
+		}
+
+		decoded, err := punycodeDecode(label[4:])
+		if err != nil {
+			return "", fmt.Errorf("label %q: %s", label, err)
+		}
+		labels[i] = decoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+func octalEscape(s string, i int) (byte, bool) {
+	if s[i] != '\\' || i+3 >= len(s) || !isOctal(s[i+1]) || !isOctal(s[i+2]) || !isOctal(s[i+3]) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s[i+1:i+4], 8, 8)
+	if err != nil {
+		return 0, false
+	}
+	return byte(n), true
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Punycode (RFC 3492) parameters.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyThreshold(k, bias int) int {
+	switch {
+	case k <= bias+punyTMin:
+		return punyTMin
+	case k >= bias+punyTMax:
+		return punyTMax
+	}
+	return k - bias
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punycodeEncode(s string) (string, error) {
+	runes := []rune(s)
+	output := []byte{}
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			output = append(output, byte(r))
+		}
+	}
+	basic := len(output)
+	handled := basic
+	if basic > 0 {
+		output = append(output, '-')
+	}
+
+	n, delta, bias := punyInitialN, 0, punyInitialBias
+	for handled < len(runes) {
+		m := int(^uint(0) >> 1)
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		if (m-n)*(handled+1) < 0 {
+			return "", errors.New("punycode overflow")
+		}
+		delta += (m - n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := punyThreshold(k, bias)
+				if q < t {
+					break
+				}
+				output = append(output, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			output = append(output, punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+
+	return string(output), nil
+}
+
+func punycodeDecode(s string) (string, error) {
+	output := []rune{}
+	pos := 0
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		for _, r := range s[:i] {
+			if r >= utf8.RuneSelf {
+				return "", errors.New("non-ASCII character in punycode")
+			}
+			output = append(output, r)
+		}
+		pos = i + 1
+	}
+
+	n, i, bias := punyInitialN, 0, punyInitialBias
+	for pos < len(s) {
+		oldi, w := i, 1
+		for k := punyBase; ; k += punyBase {
+			if pos >= len(s) {
+				return "", errors.New("truncated punycode")
+			}
+			c := s[pos]
+			pos++
+
+			var digit int
+			switch {
+			case c >= 'a' && c <= 'z':
+				digit = int(c - 'a')
+			case c >= 'A' && c <= 'Z':
+				digit = int(c - 'A')
+			case c >= '0' && c <= '9':
+				digit = int(c-'0') + 26
+			default:
+				return "", fmt.Errorf("invalid punycode character %q", c)
+			}
+
+			i += digit * w
+			t := punyThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			w *= punyBase - t
+			if w > 1<<24 {
+				return "", errors.New("punycode overflow")
+			}
+		}
+
+		bias = punyAdapt(i-oldi, len(output)+1, oldi == 0)
+		n += i / (len(output) + 1)
+		i %= len(output) + 1
+		if n > utf8.MaxRune {
+			return "", errors.New("punycode overflow")
+		}
+
+		output = append(output[:i], append([]rune{rune(n)}, output[i:]...)...)
+		i++
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,157 @@
+package route53
+
+import "testing"
+
+func TestEncodeDecodeName(t *testing.T) {
+	tests := []struct {
+		name, encoded, decoded string
+	}{
+		{"www.example.com.", "www.example.com.", "www.example.com."},
+		{"WWW.Example.COM.", "www.example.com.", "WWW.Example.COM."},
+		{"*.example.com.", `\052.example.com.`, "*.example.com."},
+		{`\052.example.com.`, `\052.example.com.`, "*.example.com."},
+		{`a\056b.example.com.`, `a\056b.example.com.`, `a\056b.example.com.`},
+		{`a\134b.example.com.`, `a\134b.example.com.`, `a\134b.example.com.`},
+		{"a b.example.com.", `a\040b.example.com.`, "a b.example.com."},
+		{"_sip._tcp.example.com.", "_sip._tcp.example.com.", "_sip._tcp.example.com."},
+	}
+
+	for _, test := range tests {
+		if got := EncodeName(test.name); got != test.encoded {
+			t.Errorf("EncodeName(%q) = %q, want %q", test.name, got, test.encoded)
+		}
+		if got := DecodeName(test.name); got != test.decoded {
+			t.Errorf("DecodeName(%q) = %q, want %q", test.name, got, test.decoded)
+		}
+	}
+}
+
+func TestNameComparisons(t *testing.T) {
+	if got := CanonicalName(`\052.Example.com`); got != "*.example.com." {
+		t.Errorf("CanonicalName = %q", got)
+	}
+	if !NamesEqual(`\052.example.com.`, "*.EXAMPLE.com") {
+		t.Error("NamesEqual: escaped and plain names differ")
+	}
+	if NamesEqual("a.example.com.", "b.example.com.") {
+		t.Error("NamesEqual: different names are equal")
+	}
+
+	subdomains := []struct {
+		name, zone string
+		want       bool
+	}{
+		{"example.com.", "example.com", true},
+		{"www.Example.com.", "example.com.", true},
+		{"notexample.com.", "example.com.", false},
+		{"example.com.", "www.example.com.", false},
+		{"anything.", ".", true},
+	}
+	for _, test := range subdomains {
+		if got := IsSubdomain(test.name, test.zone); got != test.want {
+			t.Errorf("IsSubdomain(%q, %q) = %v", test.name, test.zone, got)
+		}
+	}
+}
+
+func TestAbsoluteRelativeName(t *testing.T) {
+	absolute := []struct {
+		name, zone, absolute string
+	}{
+		{"@", "example.com", "example.com."},
+		{"", "example.com.", "example.com."},
+		{"www", "example.com.", "www.example.com."},
+		{"www.example.net.", "example.com.", "www.example.net."},
+	}
+	for _, test := range absolute {
+		if got := AbsoluteName(test.name, test.zone); got != test.absolute {
+			t.Errorf("AbsoluteName(%q, %q) = %q, want %q", test.name, test.zone, got, test.absolute)
+		}
+	}
+
+	relative := []struct {
+		name, zone, relative string
+	}{
+		{"example.com.", "example.com", "@"},
+		{"WWW.example.com.", "example.com.", "www"},
+		{`\052.a.example.com.`, "example.com.", "*.a"},
+		{"www.example.net.", "example.com.", "www.example.net."},
+		{"notexample.com.", "example.com.", "notexample.com."},
+	}
+	for _, test := range relative {
+		if got := RelativeName(test.name, test.zone); got != test.relative {
+			t.Errorf("RelativeName(%q, %q) = %q, want %q", test.name, test.zone, got, test.relative)
+		}
+	}
+}
+
+// Sample strings from RFC 3492 section 7.1, plus common IDNA examples.
+var punycodeTests = []struct {
+	unicode, punycode string
+}{
+	{"ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+	{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+	{"Pročprostěnemluvíčesky", "Proprostnemluvesky-uyb24dma41a"},
+	{"למההםפשוטלאמדבריםעברית", "4dbcagdahymbxekheh6e0a7fei0b"},
+	{"なぜみんな日本語を話してくれないのか", "n8jok5ay5dzabd5bym9f0cm5685rrjetr6pdxa"},
+	{"TạisaohọkhôngthểchỉnóitiếngViệt", "TisaohkhngthchnitingVit-kjcr8268qyxafd2f1b9g"},
+	{"3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+	{"安室奈美恵-with-SUPER-MONKEYS", "-with-SUPER-MONKEYS-pc58ag80a8qai00g7n9n"},
+	{"MajiでKoiする5秒前", "MajiKoi5-783gue6qz075azm5e"},
+	{"パフィーdeルンバ", "de-jg4avhby1noc0d"},
+	{"そのスピードで", "d9juau41awczczp"},
+	{"-> $1.00 <-", "-> $1.00 <--"},
+	{"bücher", "bcher-kva"},
+	{"münchen", "mnchen-3ya"},
+}
+
+func TestPunycode(t *testing.T) {
+	for _, test := range punycodeTests {
+		encoded, err := punycodeEncode(test.unicode)
+		if err != nil {
+			t.Errorf("punycodeEncode(%q): %s", test.unicode, err)
+		} else if encoded != test.punycode {
+			t.Errorf("punycodeEncode(%q) = %q, want %q", test.unicode, encoded, test.punycode)
+		}
+
+		decoded, err := punycodeDecode(test.punycode)
+		if err != nil {
+			t.Errorf("punycodeDecode(%q): %s", test.punycode, err)
+		} else if decoded != test.unicode {
+			t.Errorf("punycodeDecode(%q) = %q, want %q", test.punycode, decoded, test.unicode)
+		}
+	}
+
+	for _, bad := range []string{"a-b-!", "99999999999"} {
+		if _, err := punycodeDecode(bad); err == nil {
+			t.Errorf("punycodeDecode(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestIDNNames(t *testing.T) {
+	tests := []struct {
+		unicode, ascii string
+	}{
+		{"bücher.example.com.", "xn--bcher-kva.example.com."},
+		{"München.de.", "xn--mnchen-3ya.de."},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"www.example.com.", "www.example.com."},
+		{"WWW.Bücher.Example.COM.", "www.xn--bcher-kva.example.com."},
+	}
+
+	for _, test := range tests {
+		ascii, err := ToASCIIName(test.unicode)
+		if err != nil || ascii != test.ascii {
+			t.Errorf("ToASCIIName(%q) = %q, %v, want %q", test.unicode, ascii, err, test.ascii)
+		}
+	}
+
+	unicode, err := ToUnicodeName("XN--bcher-kva.example.com.")
+	if err != nil || unicode != "bücher.example.com." {
+		t.Errorf("ToUnicodeName = %q, %v", unicode, err)
+	}
+	if _, err := ToASCIIName("\xff.example.com."); err == nil {
+		t.Error("ToASCIIName: expected an error for invalid UTF-8")
+	}
+}
@@ -0,0 +1,122 @@
+package route53
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits Route53 applies when it evaluates a health check.
+const (
+	tcpProbeTimeout     = 10 * time.Second
+	httpConnectTimeout  = 4 * time.Second
+	httpResponseTimeout = 2 * time.Second
+	searchStringWindow  = 5120
+)
+
+// Probe evaluates the health check locally with the same semantics Route53
+// uses, so a bad port, path or search string is caught before the check is
+// created. A nil error means Route53 would consider the endpoint healthy.
+func (config HealthCheckConfig) Probe() error {
+	host := config.IPAddress
+	if host == "" {
+		host = config.FullyQualifiedDomainName
+	}
+	if host == "" {
+		return errors.New("health check needs an IP address or FQDN")
+	}
+
+	port := config.Port
+	if port == 0 {
+		switch config.Type {
+		case "HTTP", "HTTP_STR_MATCH":
+			port = 80
+		case "HTTPS", "HTTPS_STR_MATCH":
+			port = 443
+		default:
+			return fmt.Errorf("%s health check needs a port", config.Type)
+		}
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+
+	switch config.Type {
+	case "TCP":
+		conn, err := net.DialTimeout("tcp", addr, tcpProbeTimeout)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		return nil
+	case "HTTP", "HTTP_STR_MATCH":
+		return config.probeHTTP("http", addr, port)
+	case "HTTPS", "HTTPS_STR_MATCH":
+		return config.probeHTTP("https", addr, port)
+	}
+
+	return fmt.Errorf("unknown health check type: %s", config.Type)
+}
+
+func (config HealthCheckConfig) probeHTTP(scheme, addr string, port uint16) error {
+	path := config.ResourcePath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	hreq, err := http.NewRequest("GET", scheme+"://"+addr+path, nil)
+	if err != nil {
+		return err
+	}
+
+	// Route53 sends the FQDN (or the IP address when there is none) as the
+	// Host header, with the port appended unless it is 80 or 443.
+	hreq.Host = config.FullyQualifiedDomainName
+	if hreq.Host == "" {
+		hreq.Host = config.IPAddress
+	}
+	if port != 80 && port != 443 {
+		hreq.Host = net.JoinHostPort(hreq.Host, strconv.Itoa(int(port)))
+	}
+
+	// Route53 neither validates certificates nor follows redirects, so use
+	// the transport directly rather than an http.Client.
+	transport := &http.Transport{
+		DialContext:           (&net.Dialer{Timeout: httpConnectTimeout}).DialContext,
+		TLSHandshakeTimeout:   httpConnectTimeout,
+		ResponseHeaderTimeout: httpResponseTimeout,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         config.FullyQualifiedDomainName,
+		},
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+
+	hres, err := transport.RoundTrip(hreq)
+	if err != nil {
+		return err
+	}
+	defer hres.Body.Close()
+
+	if hres.StatusCode < 200 || hres.StatusCode >= 400 {
+		return fmt.Errorf("unhealthy status: %s", hres.Status)
+	}
+
+	if config.Type == "HTTP_STR_MATCH" || config.Type == "HTTPS_STR_MATCH" {
+		body, err := ioutil.ReadAll(io.LimitReader(hres.Body, searchStringWindow))
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(body), config.SearchString) {
+			return fmt.Errorf("search string %q not found in first %d bytes", config.SearchString, searchStringWindow)
+		}
+	}
+
+	return nil
+}
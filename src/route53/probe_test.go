@@ -0,0 +1,160 @@
+package route53
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// probeConfig returns a config of the given type pointing at server.
+func probeConfig(t *testing.T, server *httptest.Server, checkType string) HealthCheckConfig {
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return HealthCheckConfig{IPAddress: host, Port: uint16(n), Type: checkType, ResourcePath: "/health"}
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+
+	config := HealthCheckConfig{IPAddress: "127.0.0.1", Port: uint16(addr.Port), Type: "TCP"}
+	if err := config.Probe(); err != nil {
+		t.Errorf("open port: %s", err)
+	}
+
+	listener.Close()
+	if err := config.Probe(); err == nil {
+		t.Error("closed port: expected an error")
+	}
+}
+
+func TestProbeHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status  int
+		healthy bool
+	}{
+		{200, true},
+		{204, true},
+		{301, true},
+		{399, true},
+		{404, false},
+		{500, false},
+		{503, false},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+		}))
+
+		err := probeConfig(t, server, "HTTP").Probe()
+		if test.healthy && err != nil {
+			t.Errorf("status %d: %s", test.status, err)
+		}
+		if !test.healthy && err == nil {
+			t.Errorf("status %d: expected an error", test.status)
+		}
+
+		server.Close()
+	}
+}
+
+func TestProbeHTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	if err := probeConfig(t, server, "HTTPS").Probe(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProbeDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			followed = true
+			w.WriteHeader(500)
+			return
+		}
+		http.Redirect(w, r, "/moved", http.StatusFound)
+	}))
+	defer server.Close()
+
+	if err := probeConfig(t, server, "HTTP").Probe(); err != nil {
+		t.Error(err)
+	}
+	if followed {
+		t.Error("redirect was followed")
+	}
+}
+
+func TestProbeSearchString(t *testing.T) {
+	tests := []struct {
+		body    string
+		healthy bool
+	}{
+		{"status: ok", true},
+		{strings.Repeat("x", searchStringWindow-2) + "ok", true},
+		{strings.Repeat("x", searchStringWindow-1) + "ok", false},
+		{strings.Repeat("x", searchStringWindow) + "ok", false},
+		{"status: down", false},
+	}
+
+	for i, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(test.body))
+		}))
+
+		config := probeConfig(t, server, "HTTP_STR_MATCH")
+		config.SearchString = "ok"
+		err := config.Probe()
+		if test.healthy && err != nil {
+			t.Errorf("%d: %s", i, err)
+		}
+		if !test.healthy && err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+
+		server.Close()
+	}
+}
+
+func TestProbeHostHeader(t *testing.T) {
+	host := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer server.Close()
+
+	config := probeConfig(t, server, "HTTP")
+	config.FullyQualifiedDomainName = "www.example.com"
+	if err := config.Probe(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "www.example.com:" + strconv.Itoa(int(config.Port)); host != want {
+		t.Errorf("Host header %q, want %q", host, want)
+	}
+
+	config.FullyQualifiedDomainName = ""
+	if err := config.Probe(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "127.0.0.1:" + strconv.Itoa(int(config.Port)); host != want {
+		t.Errorf("Host header %q, want %q", host, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -32,12 +33,19 @@ type RRSet struct {
 	// Fail Syntax
 	Failover string `xml:",omitempty"`
 
-	// Weight Syntax
-	Weight uint8
+	// Weight Syntax. Nil for record sets that are not weighted; a weight
+	// of 0 is sent as such.
+	Weight *uint8 `xml:",omitempty"`
 
 	// Latency Syntax
 	Region string `xml:",omitempty"`
 
+	// Geolocation Syntax
+	GeoLocation *GeoLocation `xml:",omitempty"`
+
+	// Multivalue Answer Syntax
+	MultiValueAnswer bool `xml:",omitempty"`
+
 	// TTL for the record
 	TTL uint `xml:",omitempty"`
 
@@ -49,6 +57,9 @@ type RRSet struct {
 
 	// Health Checks
 	HealthCheckID string `xml:"HealthCheckId,omitempty"`
+
+	// Set on record sets created by a traffic policy instance
+	TrafficPolicyInstanceID string `xml:"TrafficPolicyInstanceId,omitempty"`
 }
 
 type ResourceRecords struct {
@@ -59,6 +70,15 @@ type ResourceRecord struct {
 	Value string
 }
 
+// GeoLocation selects the users a record set answers: either a continent, or
+// a country optionally narrowed to a subdivision. CountryCode "*" is the
+// default record set for locations no other record set matches.
+type GeoLocation struct {
+	ContinentCode   string `xml:",omitempty"`
+	CountryCode     string `xml:",omitempty"`
+	SubdivisionCode string `xml:",omitempty"`
+}
+
 type AliasTarget struct {
 	HostedZoneID         string `xml:"HostedZoneId"`
 	DNSName              string
@@ -75,22 +95,40 @@ type ListRRSetResponse struct {
 	RRSets               []RRSet  `xml:"ResourceRecordSets>ResourceRecordSet"`
 	IsTruncated          bool
 	NextRecordName       string
+	NextRecordType       string
 	NextRecordIdentifier string
 	MaxItems             uint
 }
 
+// isZoneApexRecord reports whether rrset is the SOA or NS record set at the
+// apex of the zone, which Route53 manages itself.
+func isZoneApexRecord(rrset RRSet, zoneName string) bool {
+	return NamesEqual(rrset.Name, zoneName) && (rrset.Type == "SOA" || rrset.Type == "NS")
+}
+
+// isTrafficPolicyRecord reports whether rrset was created by a traffic policy
+// instance, which has to be changed or deleted through the instance.
+func isTrafficPolicyRecord(rrset RRSet) bool {
+	return rrset.TrafficPolicyInstanceID != ""
+}
+
+// rrsetKey identifies a record set within a zone.
+func rrsetKey(rrset RRSet) string {
+	return CanonicalName(rrset.Name) + " " + rrset.Type + " " + rrset.SetIdentifier
+}
+
 // Route53 API requests.
 
 func (r53 *Route53) ChangeRRSet(zoneID string, changes []RRSetChange, comment string) (ChangeInfo, error) {
 	xmlReq := &ChangeRRSetRequest{
-		XMLNS:   "https://route53.amazonaws.com/doc/2012-12-12/",
+		XMLNS:   "https://route53.amazonaws.com/doc/2013-04-01/",
 		Comment: comment,
 		Changes: changes,
 	}
 
 	req := request{
 		method: "POST",
-		path:   fmt.Sprintf("/2012-12-12/hostedzone/%s/rrset", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/rrset", strings.Replace(zoneID, "/hostedzone/", "", -1)),
 		body:   xmlReq,
 	}
 
@@ -107,7 +145,7 @@ func (r53 *Route53) ChangeRRSet(zoneID string, changes []RRSetChange, comment st
 func (r53 *Route53) ListRRSets(zoneID string) ([]RRSet, error) {
 	req := request{
 		method: "GET",
-		path:   fmt.Sprintf("/2012-12-12/hostedzone/%s/rrset", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/rrset", strings.Replace(zoneID, "/hostedzone/", "", -1)),
 	}
 
 	xmlRes := &ListRRSetResponse{}
@@ -122,8 +160,14 @@ func (r53 *Route53) ListRRSets(zoneID string) ([]RRSet, error) {
 	for xmlRes.IsTruncated {
 		req.params = &url.Values{
 			"name": []string{xmlRes.NextRecordName},
+			"type": []string{xmlRes.NextRecordType},
+		}
+		if xmlRes.NextRecordIdentifier != "" {
+			req.params.Set("identifier", xmlRes.NextRecordIdentifier)
 		}
 
+		// Decoding appends to slices, so each page needs a fresh response.
+		xmlRes = &ListRRSetResponse{}
 		if err := r53.run(req, xmlRes); err != nil {
 			return []RRSet{}, err
 		}
@@ -133,9 +177,61 @@ func (r53 *Route53) ListRRSets(zoneID string) ([]RRSet, error) {
 	return rrsets, nil
 }
 
+// Record sets asked for per page when looking up a single name and type.
+const rrsetLookupMaxItems = 10
+
+// listRRSetsAt returns the record sets of the given name and type, one per
+// set identifier, without listing the rest of the zone.
+func (r53 *Route53) listRRSetsAt(zoneID, name, rrtype string) ([]RRSet, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/rrset", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		params: &url.Values{
+			"name":     []string{name},
+			"type":     []string{rrtype},
+			"maxitems": []string{strconv.Itoa(rrsetLookupMaxItems)},
+		},
+	}
+
+	want := rrsetKey(RRSet{Name: name, Type: rrtype})
+	rrsets := []RRSet{}
+
+	for {
+		xmlRes := &ListRRSetResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []RRSet{}, err
+		}
+
+		// Listing starts at name and type but carries on past them.
+		for _, rrset := range xmlRes.RRSets {
+			if rrsetKey(RRSet{Name: rrset.Name, Type: rrset.Type}) != want {
+				return rrsets, nil
+			}
+			rrsets = append(rrsets, rrset)
+		}
+
+		if !xmlRes.IsTruncated {
+			return rrsets, nil
+		}
+		req.params.Set("name", xmlRes.NextRecordName)
+		req.params.Set("type", xmlRes.NextRecordType)
+		req.params.Del("identifier")
+		if xmlRes.NextRecordIdentifier != "" {
+			req.params.Set("identifier", xmlRes.NextRecordIdentifier)
+		}
+	}
+}
+
 // Convenience functions on AWS APIs.
 
 func (z *HostedZone) ChangeRRSet(changes []RRSetChange, comment string) (ChangeInfo, error) {
+	if z.r53.Registry != nil {
+		var err error
+		if changes, err = z.r53.Registry.track(z.r53, z.ID, changes); err != nil {
+			return ChangeInfo{}, err
+		}
+	}
+
 	return z.r53.ChangeRRSet(z.ID, changes, comment)
 }
 
@@ -0,0 +1,246 @@
+package route53
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Registry tracks which owner manages each record set. When set on a client,
+// changes made through HostedZone.ChangeRRSet (and everything built on it)
+// also maintain a companion TXT record per name and type holding the owner
+// ID, the managing tool and the time of the last change, and changes to
+// record sets that belong to another owner, or to no owner, are refused
+// unless Force is set.
+type Registry struct {
+	OwnerID string
+
+	// Recorded in the companion records. Defaults to "route53".
+	ManagedBy string
+
+	// Companion records are named <Prefix><type>.<name>, e.g.
+	// _owner-a.www.example.com. for www.example.com. A. Defaults to "_owner-".
+	Prefix string
+
+	// Change record sets regardless of who owns them.
+	Force bool
+}
+
+// Owner is the content of a companion record.
+type Owner struct {
+	ID        string
+	ManagedBy string
+	Timestamp time.Time
+}
+
+const ownerRecordTTL = 300
+
+func (reg *Registry) prefix() string {
+	if reg.Prefix == "" {
+		return "_owner-"
+	}
+	return reg.Prefix
+}
+
+func (reg *Registry) managedBy() string {
+	if reg.ManagedBy == "" {
+		return "route53"
+	}
+	return reg.ManagedBy
+}
+
+// OwnerRecordName returns the name of the companion record for record sets
+// of the given name and type.
+func (reg *Registry) OwnerRecordName(name, rrtype string) string {
+	return reg.prefix() + strings.ToLower(rrtype) + "." + CanonicalName(name)
+}
+
+func (reg *Registry) isOwnerRecord(rrset RRSet) bool {
+	return rrset.Type == "TXT" && strings.HasPrefix(CanonicalName(rrset.Name), reg.prefix())
+}
+
+func (reg *Registry) ownerRecord(name, rrtype string) RRSet {
+	value := fmt.Sprintf("\"owner=%s,managed-by=%s,timestamp=%s\"",
+		reg.OwnerID, reg.managedBy(), time.Now().UTC().Format(time.RFC3339))
+
+	return RRSet{
+		Name: reg.OwnerRecordName(name, rrtype),
+		Type: "TXT",
+		TTL:  ownerRecordTTL,
+		ResourceRecords: &ResourceRecords{
+			ResourceRecord: []ResourceRecord{{Value: value}},
+		},
+	}
+}
+
+// ParseOwner reads a companion record. ok is false if rrset is not one.
+func ParseOwner(rrset RRSet) (owner Owner, ok bool) {
+	if rrset.Type != "TXT" || rrset.ResourceRecords == nil || len(rrset.ResourceRecords.ResourceRecord) != 1 {
+		return Owner{}, false
+	}
+
+	value := strings.Trim(rrset.ResourceRecords.ResourceRecord[0].Value, "\"")
+	for _, field := range strings.Split(value, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "owner":
+			owner.ID, ok = kv[1], true
+		case "managed-by":
+			owner.ManagedBy = kv[1]
+		case "timestamp":
+			owner.Timestamp, _ = time.Parse(time.RFC3339, kv[1])
+		}
+	}
+
+	return owner, ok
+}
+
+// OwnsFunc returns a PlanOptions.Owns filter that accepts the record sets in
+// current owned by this registry's owner. Companion records are never owned;
+// they are maintained alongside the record sets they describe.
+func (reg *Registry) OwnsFunc(current []RRSet) func(RRSet) bool {
+	owners := map[string]string{}
+	for _, rrset := range current {
+		if owner, ok := ParseOwner(rrset); ok && reg.isOwnerRecord(rrset) {
+			owners[CanonicalName(rrset.Name)] = owner.ID
+		}
+	}
+
+	return func(rrset RRSet) bool {
+		if reg.isOwnerRecord(rrset) {
+			return false
+		}
+		name := reg.OwnerRecordName(rrset.Name, rrset.Type)
+		return owners[name] == reg.OwnerID
+	}
+}
+
+// track checks that changes only touch record sets owned by the registry's
+// owner and adds the companion record changes that go with them. Each name
+// and type changed is looked up on its own, along with its companion record.
+func (reg *Registry) track(r53 *Route53, zoneID string, changes []RRSetChange) ([]RRSetChange, error) {
+	return reg.trackWith(changes, func(name, rrtype string) ([]RRSet, *RRSet, error) {
+		current, err := r53.listRRSetsAt(zoneID, name, rrtype)
+		if err != nil {
+			return []RRSet{}, nil, err
+		}
+		owners, err := r53.listRRSetsAt(zoneID, reg.OwnerRecordName(name, rrtype), "TXT")
+		if err != nil || len(owners) == 0 {
+			return current, nil, err
+		}
+		return current, &owners[0], nil
+	})
+}
+
+// trackListed is track for callers that have just listed the whole zone, so
+// nothing needs looking up again.
+func (reg *Registry) trackListed(zone []RRSet, changes []RRSetChange) ([]RRSetChange, error) {
+	// Current record sets by the name of their companion record, and the
+	// companion records themselves.
+	current := map[string][]RRSet{}
+	owners := map[string]RRSet{}
+	for _, rrset := range zone {
+		if reg.isOwnerRecord(rrset) {
+			owners[CanonicalName(rrset.Name)] = rrset
+			continue
+		}
+		name := reg.OwnerRecordName(rrset.Name, rrset.Type)
+		current[name] = append(current[name], rrset)
+	}
+
+	return reg.trackWith(changes, func(name, rrtype string) ([]RRSet, *RRSet, error) {
+		ownerName := reg.OwnerRecordName(name, rrtype)
+		if owner, ok := owners[ownerName]; ok {
+			return current[ownerName], &owner, nil
+		}
+		return current[ownerName], nil, nil
+	})
+}
+
+// trackWith does the work of track, finding the record sets of a name and
+// type and their companion record with lookup.
+func (reg *Registry) trackWith(changes []RRSetChange, lookup func(name, rrtype string) ([]RRSet, *RRSet, error)) ([]RRSetChange, error) {
+	explicit := map[string]bool{}
+	for _, change := range changes {
+		explicit[rrsetKey(change.RRSet)] = true
+	}
+
+	// Changes per name and type, so deletes of some set identifiers only
+	// remove the companion when nothing of that name and type is left.
+	type target struct {
+		current []RRSet
+		owner   *RRSet
+		deleted map[string]bool
+		written bool
+	}
+	targets := map[string]*target{}
+
+	for _, change := range changes {
+		if reg.isOwnerRecord(change.RRSet) {
+			continue
+		}
+
+		name := reg.OwnerRecordName(change.RRSet.Name, change.RRSet.Type)
+		t, ok := targets[name]
+		if !ok {
+			t = &target{deleted: map[string]bool{}}
+			targets[name] = t
+
+			var err error
+			if t.current, t.owner, err = lookup(change.RRSet.Name, change.RRSet.Type); err != nil {
+				return changes, err
+			}
+		}
+
+		if reg.Force {
+			continue
+		}
+		if t.owner != nil {
+			if owner, _ := ParseOwner(*t.owner); owner.ID != reg.OwnerID {
+				return changes, fmt.Errorf("%s %s is owned by %q", change.RRSet.Name, change.RRSet.Type, owner.ID)
+			}
+		} else if len(t.current) > 0 && change.Action != "CREATE" {
+			return changes, fmt.Errorf("%s %s has no owner", change.RRSet.Name, change.RRSet.Type)
+		}
+	}
+
+	tracked := []RRSetChange{}
+	for _, change := range changes {
+		tracked = append(tracked, change)
+		if reg.isOwnerRecord(change.RRSet) {
+			continue
+		}
+
+		name := reg.OwnerRecordName(change.RRSet.Name, change.RRSet.Type)
+		t := targets[name]
+		ownerRecord := reg.ownerRecord(change.RRSet.Name, change.RRSet.Type)
+		if explicit[rrsetKey(ownerRecord)] {
+			continue
+		}
+
+		if change.Action != "DELETE" {
+			if !t.written {
+				tracked = append(tracked, RRSetChange{Action: "UPSERT", RRSet: ownerRecord})
+				t.written = true
+			}
+			continue
+		}
+
+		t.deleted[rrsetKey(change.RRSet)] = true
+		remaining := 0
+		for _, rrset := range t.current {
+			if !t.deleted[rrsetKey(rrset)] {
+				remaining++
+			}
+		}
+		if remaining == 0 && !t.written && t.owner != nil {
+			tracked = append(tracked, RRSetChange{Action: "DELETE", RRSet: *t.owner})
+			t.owner = nil
+		}
+	}
+
+	return tracked, nil
+}
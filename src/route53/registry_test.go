@@ -0,0 +1,192 @@
+package route53
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+const registryZone = `<ListResourceRecordSetsResponse>
+	<ResourceRecordSets>
+		<ResourceRecordSet><Name>_owner-a.mine.example.com.</Name><Type>TXT</Type><TTL>300</TTL>
+			<ResourceRecords><ResourceRecord><Value>"owner=me,managed-by=route53,timestamp=2013-04-01T00:00:00Z"</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>_owner-a.theirs.example.com.</Name><Type>TXT</Type><TTL>300</TTL>
+			<ResourceRecords><ResourceRecord><Value>"owner=them,managed-by=route53,timestamp=2013-04-01T00:00:00Z"</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>mine.example.com.</Name><Type>A</Type><SetIdentifier>one</SetIdentifier><Weight>1</Weight><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>mine.example.com.</Name><Type>A</Type><SetIdentifier>two</SetIdentifier><Weight>1</Weight><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.2</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>theirs.example.com.</Name><Type>A</Type><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.3</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>unowned.example.com.</Name><Type>A</Type><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.4</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+	</ResourceRecordSets>
+	<IsTruncated>false</IsTruncated><MaxItems>100</MaxItems>
+</ListResourceRecordSetsResponse>`
+
+// lookupServer answers ListResourceRecordSets from the record sets in listing,
+// starting at the name, type and identifier asked for and honouring maxitems.
+// It returns the query of every request made.
+func lookupServer(t *testing.T, listing string) (*Route53, *[]string) {
+	zone := ListRRSetResponse{}
+	if err := xml.Unmarshal([]byte(listing), &zone); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := []string{}
+	r53 := handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "GET /2013-04-01/hostedzone/Z1/rrset" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+			return
+		}
+		query := r.URL.Query()
+		queries = append(queries, query.Encode())
+
+		from := RRSet{Name: query.Get("name"), Type: query.Get("type"), SetIdentifier: query.Get("identifier")}
+		start := len(zone.RRSets)
+		for i, rrset := range zone.RRSets {
+			if from.SetIdentifier == "" {
+				rrset.SetIdentifier = ""
+			}
+			if rrsetKey(rrset) == rrsetKey(from) {
+				start = i
+				break
+			}
+		}
+		maxItems, _ := strconv.Atoi(query.Get("maxitems"))
+		if maxItems == 0 {
+			maxItems = 100
+		}
+
+		res := ListRRSetResponse{RRSets: zone.RRSets[start:], MaxItems: uint(maxItems)}
+		if len(res.RRSets) > maxItems {
+			next := res.RRSets[maxItems]
+			res.RRSets = res.RRSets[:maxItems]
+			res.IsTruncated = true
+			res.NextRecordName, res.NextRecordType, res.NextRecordIdentifier = next.Name, next.Type, next.SetIdentifier
+		}
+		data, _ := xml.Marshal(res)
+		w.Write(data)
+	}))
+
+	return r53, &queries
+}
+
+func registryRRSet(name, identifier string) RRSet {
+	return RRSet{Name: name, Type: "A", SetIdentifier: identifier, TTL: 60,
+		ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: "192.0.2.9"}}}}
+}
+
+func TestRegistryTrack(t *testing.T) {
+	r53, queries := lookupServer(t, registryZone)
+	reg := &Registry{OwnerID: "me"}
+
+	changes := []RRSetChange{
+		{Action: "UPSERT", RRSet: registryRRSet("mine.example.com.", "one")},
+		{Action: "CREATE", RRSet: registryRRSet("new.example.com.", "")},
+		{Action: "CREATE", RRSet: registryRRSet("new2.example.com.", "")},
+	}
+	tracked, err := reg.track(r53, "Z1", changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each name and type and its companion are looked up, not the zone.
+	if len(*queries) != 6 {
+		t.Errorf("%d lookups, want 6: %v", len(*queries), *queries)
+	}
+	for _, query := range *queries {
+		if query == "" {
+			t.Errorf("listed the whole zone")
+		}
+	}
+
+	companions := []string{}
+	for _, change := range tracked {
+		if reg.isOwnerRecord(change.RRSet) {
+			companions = append(companions, change.Action+" "+change.RRSet.Name)
+		}
+	}
+	want := []string{"UPSERT _owner-a.mine.example.com.", "UPSERT _owner-a.new.example.com.", "UPSERT _owner-a.new2.example.com."}
+	if len(companions) != len(want) {
+		t.Fatalf("companion changes %v, want %v", companions, want)
+	}
+	for i := range want {
+		if companions[i] != want[i] {
+			t.Errorf("companion changes %v, want %v", companions, want)
+		}
+	}
+
+	// The companion goes only once every set identifier is deleted.
+	for _, deleted := range [][]string{{"one"}, {"one", "two"}} {
+		changes := []RRSetChange{}
+		for _, identifier := range deleted {
+			changes = append(changes, RRSetChange{Action: "DELETE", RRSet: registryRRSet("mine.example.com.", identifier)})
+		}
+		tracked, err := reg.track(r53, "Z1", changes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		removed := len(tracked) > len(changes)
+		if removed != (len(deleted) == 2) {
+			t.Errorf("deleting %v: tracked %+v", deleted, tracked)
+		}
+	}
+
+	for _, name := range []string{"theirs.example.com.", "unowned.example.com."} {
+		changes := []RRSetChange{{Action: "UPSERT", RRSet: registryRRSet(name, "")}}
+		if _, err := reg.track(r53, "Z1", changes); err == nil {
+			t.Errorf("%s: expected an ownership error", name)
+		}
+		force := &Registry{OwnerID: "me", Force: true}
+		if _, err := force.track(r53, "Z1", changes); err != nil {
+			t.Errorf("%s with Force: %s", name, err)
+		}
+	}
+}
+
+func TestListRRSetsAtPages(t *testing.T) {
+	// More set identifiers than a lookup returns at once, followed by a
+	// record set of another name.
+	listing := "<ListResourceRecordSetsResponse><ResourceRecordSets>"
+	for i := 0; i < rrsetLookupMaxItems+2; i++ {
+		listing += "<ResourceRecordSet><Name>w.example.com.</Name><Type>A</Type><SetIdentifier>" + strconv.Itoa(i) +
+			"</SetIdentifier><Weight>1</Weight><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>"
+	}
+	listing += "<ResourceRecordSet><Name>x.example.com.</Name><Type>A</Type><TTL>60</TTL>" +
+		"<ResourceRecords><ResourceRecord><Value>192.0.2.2</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>" +
+		"</ResourceRecordSets></ListResourceRecordSetsResponse>"
+
+	r53, _ := lookupServer(t, listing)
+	current, err := r53.listRRSetsAt("Z1", "w.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current) != rrsetLookupMaxItems+2 {
+		t.Errorf("found %d record sets, want %d", len(current), rrsetLookupMaxItems+2)
+	}
+}
+
+func TestRegistryTrackListed(t *testing.T) {
+	zone := ListRRSetResponse{}
+	if err := xml.Unmarshal([]byte(registryZone), &zone); err != nil {
+		t.Fatal(err)
+	}
+	reg := &Registry{OwnerID: "me"}
+
+	changes := []RRSetChange{{Action: "DELETE", RRSet: registryRRSet("mine.example.com.", "one")},
+		{Action: "DELETE", RRSet: registryRRSet("mine.example.com.", "two")}}
+	tracked, err := reg.trackListed(zone.RRSets, changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracked) != 3 || tracked[2].RRSet.Name != "_owner-a.mine.example.com." {
+		t.Errorf("unexpected changes %+v", tracked)
+	}
+
+	changes = []RRSetChange{{Action: "UPSERT", RRSet: registryRRSet("theirs.example.com.", "")}}
+	if _, err := reg.trackListed(zone.RRSets, changes); err == nil {
+		t.Error("theirs.example.com.: expected an ownership error")
+	}
+}
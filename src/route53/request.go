@@ -18,8 +18,13 @@ type request struct {
 	body   interface{}
 }
 
-func (r *request) url() *url.URL {
-	url, _ := url.Parse("https://route53.amazonaws.com")
+const defaultEndpoint = "https://route53.amazonaws.com"
+
+func (r *request) url(endpoint string) *url.URL {
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	url, _ := url.Parse(endpoint)
 	url.Path = r.path
 
 	// Most requests don't have params.
@@ -30,6 +35,22 @@ func (r *request) url() *url.URL {
 	return url
 }
 
+// Error is an error returned by the Route53 API. Code is empty if the
+// response could not be parsed.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
 type errorResponse struct {
 	Type      string `xml:"Error>Type"`
 	Code      string `xml:"Error>Code"`
@@ -44,7 +65,7 @@ func (r53 *Route53) run(req request, res interface{}) error {
 func (r53 *Route53) doRun(req request, res interface{}, try int) error {
 	hreq := &http.Request{
 		Method:     req.method,
-		URL:        req.url(),
+		URL:        req.url(r53.endpoint),
 		Proto:      "HTTP/1.1",
 		ProtoMajor: 1,
 		ProtoMinor: 1,
@@ -68,16 +89,10 @@ func (r53 *Route53) doRun(req request, res interface{}, try int) error {
 		if debug {
 			ppData, _ := xml.MarshalIndent(req.body, " ", "    ")
 			ppBody_s11n := strings.Replace(string(ppData), "<AliasTarget></AliasTarget>", "- <AliasTarget></AliasTarget>", -1)
-			if !r53.IncludeWeight {
-				ppBody_s11n = strings.Replace(string(ppData), "<Weight>0</Weight>", "- <Weight>0</Weight>", -1)
-			}
 			fmt.Fprintf(os.Stderr, "-- body\n%s\n\n", xml.Header+ppBody_s11n)
 		}
 
 		body_s11n := strings.Replace(string(data), "<AliasTarget></AliasTarget>", "", -1)
-		if !r53.IncludeWeight {
-			body_s11n = strings.Replace(string(data), "<Weight>0</Weight>", "", -1)
-		}
 		hreq.Body = ioutil.NopCloser(bytes.NewBufferString(xml.Header + body_s11n))
 	}
 
@@ -113,13 +128,13 @@ func (r53 *Route53) doRun(req request, res interface{}, try int) error {
 			if debug {
 				fmt.Fprintf(os.Stderr, "-- error unmarshalling\n%s\n%s\n\n", err, string(body))
 			}
-			return fmt.Errorf("could not parse: %s", string(body))
+			return &Error{StatusCode: hres.StatusCode, Message: "could not parse: " + string(body)}
 		} else {
 			if debug {
 				ppBody, _ := xml.MarshalIndent(eres, " ", "    ")
 				fmt.Fprintf(os.Stderr, "-- body\n%s\n\n", string(ppBody))
 			}
-			return fmt.Errorf("%s: %s", eres.Code, eres.Message)
+			return &Error{StatusCode: hres.StatusCode, Code: eres.Code, Message: eres.Message, RequestID: eres.RequestID}
 		}
 	}
 
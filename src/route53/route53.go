@@ -22,9 +22,17 @@ func DebugOff() {
 }
 
 type Route53 struct {
-	auth          aws.Auth
-	authLock      sync.RWMutex
+	auth     aws.Auth
+	authLock sync.RWMutex
+
+	// Deprecated: RRSet.Weight is sent whenever it is set, including 0.
 	IncludeWeight bool
+
+	// Where requests are sent, for tests. Defaults to the Route53 API.
+	endpoint string
+
+	// Optional owner tracking for record set changes made through zones.
+	Registry *Registry
 }
 
 func (r53 *Route53) updateAuth() {
@@ -99,7 +107,7 @@ type GetChangeResponse struct {
 func (r53 *Route53) GetChange(id string) (ChangeInfo, error) {
 	req := request{
 		method: "GET",
-		path:   fmt.Sprintf("/2012-12-12/change/%s", strings.Replace(id, "/change/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/change/%s", strings.Replace(id, "/change/", "", -1)),
 	}
 
 	xmlRes := &GetChangeResponse{}
@@ -107,6 +115,7 @@ func (r53 *Route53) GetChange(id string) (ChangeInfo, error) {
 	if err := r53.run(req, xmlRes); err != nil {
 		return ChangeInfo{}, err
 	}
+	xmlRes.ChangeInfo.r53 = r53
 
 	return xmlRes.ChangeInfo, nil
 }
@@ -0,0 +1,227 @@
+package route53
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testChangeInfo = `<ChangeInfo><Id>/change/C1</Id><Status>PENDING</Status><SubmittedAt>2013-04-01T00:00:00Z</SubmittedAt></ChangeInfo>`
+
+// testServer answers requests with the canned XML responses keyed by
+// "METHOD path" and returns a client that uses it for the rest of the test.
+// Canned error responses are sent with a 400 status.
+func testServer(t *testing.T, responses map[string]string) *Route53 {
+	r53, _ := recordingServer(t, responses)
+	return r53
+}
+
+// recordingServer is testServer, also returning the requests made to it as
+// "METHOD path body".
+func recordingServer(t *testing.T, responses map[string]string) (*Route53, *[]string) {
+	requests := []string{}
+	r53 := handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(data))
+
+		if !strings.HasPrefix(r.URL.Path, "/2013-04-01/") {
+			t.Errorf("request %s %s is not to the 2013-04-01 API", r.Method, r.URL.Path)
+		}
+		body, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+			w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchResource</Code><Message>not canned</Message></Error></ErrorResponse>`))
+			return
+		}
+		if strings.HasPrefix(body, "<ErrorResponse>") {
+			w.WriteHeader(400)
+		}
+		w.Write([]byte(body))
+	}))
+
+	return r53, &requests
+}
+
+// handlerServer returns a client whose requests go to handler for the rest
+// of the test.
+func handlerServer(t *testing.T, handler http.Handler) *Route53 {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &Route53{endpoint: server.URL}
+}
+
+// weight returns w for RRSet.Weight.
+func weight(w uint8) *uint8 {
+	return &w
+}
+
+var zoneResponses = map[string]string{
+	"GET /2013-04-01/hostedzone": `<ListHostedZonesResponse>
+		<HostedZones>
+			<HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name><ResourceRecordSetCount>2</ResourceRecordSetCount></HostedZone>
+			<HostedZone><Id>/hostedzone/Z2</Id><Name>example.org.</Name><Config><PrivateZone>true</PrivateZone></Config></HostedZone>
+		</HostedZones>
+		<IsTruncated>false</IsTruncated><MaxItems>100</MaxItems>
+	</ListHostedZonesResponse>`,
+	"GET /2013-04-01/hostedzone/Z1": `<GetHostedZoneResponse>
+		<HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name></HostedZone>
+		<DelegationSet><NameServers><NameServer>ns-1.example.net</NameServer></NameServers></DelegationSet>
+	</GetHostedZoneResponse>`,
+	"POST /2013-04-01/hostedzone": `<CreateHostedZoneResponse>
+		<HostedZone><Id>/hostedzone/Z3</Id><Name>example.net.</Name></HostedZone>` + testChangeInfo + `
+		<DelegationSet><NameServers><NameServer>ns-1.example.net</NameServer></NameServers></DelegationSet>
+	</CreateHostedZoneResponse>`,
+	"GET /2013-04-01/hostedzone/Z1/rrset": `<ListResourceRecordSetsResponse>
+		<ResourceRecordSets>
+			<ResourceRecordSet><Name>example.com.</Name><Type>NS</Type><TTL>172800</TTL>
+				<ResourceRecords><ResourceRecord><Value>ns-1.example.net.</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+			<ResourceRecordSet><Name>www.example.com.</Name><Type>A</Type><TTL>60</TTL>
+				<ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		</ResourceRecordSets>
+		<IsTruncated>false</IsTruncated><MaxItems>100</MaxItems>
+	</ListResourceRecordSetsResponse>`,
+	"POST /2013-04-01/hostedzone/Z1/rrset": `<ChangeResourceRecordSetsResponse>` + testChangeInfo + `</ChangeResourceRecordSetsResponse>`,
+	"GET /2013-04-01/change/C1":            `<GetChangeResponse><ChangeInfo><Id>/change/C1</Id><Status>INSYNC</Status></ChangeInfo></GetChangeResponse>`,
+	"DELETE /2013-04-01/hostedzone/Z1":     `<DeleteHostedZoneResponse>` + testChangeInfo + `</DeleteHostedZoneResponse>`,
+}
+
+func TestListHostedZonesSetsClient(t *testing.T) {
+	r53 := testServer(t, zoneResponses)
+
+	zones, err := r53.ListHostedZones()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 2 || zones[0].ID != "/hostedzone/Z1" || !zones[1].PrivateZone {
+		t.Fatalf("unexpected zones %+v", zones)
+	}
+	for _, zone := range zones {
+		if zone.r53 != r53 {
+			t.Errorf("zone %s has no client", zone.ID)
+		}
+	}
+
+	rrsets, err := zones[0].ListRRSets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rrsets) != 2 || rrsets[1].Name != "www.example.com." {
+		t.Errorf("unexpected record sets %+v", rrsets)
+	}
+}
+
+func TestHostedZoneSetsClient(t *testing.T) {
+	r53 := testServer(t, zoneResponses)
+
+	zone, err := r53.GetHostedZone("Z1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zone.r53 != r53 {
+		t.Error("GetHostedZone: zone has no client")
+	}
+	if len(zone.NameServers) != 1 {
+		t.Errorf("GetHostedZone: name servers %v", zone.NameServers)
+	}
+
+	created, err := r53.CreateHostedZone("example.net.", "ref", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.r53 != r53 || created.ChangeInfo.r53 != r53 {
+		t.Error("CreateHostedZone: zone or change has no client")
+	}
+
+	change, err := zone.CreateRRSet(RRSet{Name: "new.example.com.", Type: "A", TTL: 60}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.r53 != r53 {
+		t.Error("CreateRRSet: change has no client")
+	}
+
+	change, err = zone.Delete()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.r53 != r53 {
+		t.Error("Delete: change has no client")
+	}
+}
+
+func TestChangeInfoSetsClient(t *testing.T) {
+	r53 := testServer(t, zoneResponses)
+
+	change, err := r53.GetChange("/change/C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.r53 != r53 || change.Status != "INSYNC" {
+		t.Errorf("unexpected change %+v", change)
+	}
+
+	change, err = r53.ChangeRRSet("Z1", []RRSetChange{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-change.PollForSync(time.Millisecond, time.Second); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHealthCheckSetsClient(t *testing.T) {
+	check := `<HealthCheck><Id>abcdef01-2345-6789-abcd-ef0123456789</Id><CallerReference>ref</CallerReference>
+		<HealthCheckConfig><IPAddress>192.0.2.1</IPAddress><Port>80</Port><Type>HTTP</Type></HealthCheckConfig></HealthCheck>`
+	path := "/2013-04-01/healthcheck/abcdef01-2345-6789-abcd-ef0123456789"
+	tagsPath := "/2013-04-01/tags/healthcheck/abcdef01-2345-6789-abcd-ef0123456789"
+
+	r53 := testServer(t, map[string]string{
+		"GET /2013-04-01/healthcheck": `<ListHealthChecksResponse><HealthChecks>` + check + check +
+			`</HealthChecks><IsTruncated>false</IsTruncated></ListHealthChecksResponse>`,
+		"GET " + path:    `<GetHealthCheckResponse>` + check + `</GetHealthCheckResponse>`,
+		"DELETE " + path: `<DeleteHealthCheckResponse/>`,
+		"GET " + tagsPath: `<ListTagsForResourceResponse><ResourceTagSet><ResourceType>healthcheck</ResourceType>
+			<Tags><Tag><Key>Name</Key><Value>web-1</Value></Tag></Tags></ResourceTagSet></ListTagsForResourceResponse>`,
+		"POST " + tagsPath: `<ChangeTagsForResourceResponse/>`,
+	})
+
+	checks, err := r53.ListHealthChecks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("unexpected health checks %+v", checks)
+	}
+	for _, c := range checks {
+		if c.r53 != r53 {
+			t.Errorf("ListHealthChecks: check %s has no client", c.ID)
+		}
+	}
+
+	c, err := r53.GetHealthCheck("abcdef01-2345-6789-abcd-ef0123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.r53 != r53 || c.HealthCheckConfig.Type != "HTTP" {
+		t.Fatalf("unexpected health check %+v", c)
+	}
+
+	tags, err := c.ListTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags["Name"] != "web-1" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if err := c.ChangeTags(Tags{"env": "test"}, nil); err != nil {
+		t.Error(err)
+	}
+	if err := c.Delete(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,200 @@
+package route53
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PlanOptions controls how a desired state is reconciled with a zone.
+type PlanOptions struct {
+	// Owns reports whether an existing record set is managed by the desired
+	// state. Record sets it rejects are never changed or deleted. By default
+	// everything except the apex SOA and NS records and the record sets of
+	// traffic policy instances is managed, or, when the client has a
+	// Registry, whatever the registry's owner owns.
+	Owns func(RRSet) bool
+
+	// Leave managed record sets that are missing from the desired state in
+	// place instead of deleting them.
+	NoDelete bool
+}
+
+// Plan is the set of changes that brings a zone to a desired state. Skipped
+// holds desired record sets that collide with a record set not owned by the
+// plan.
+type Plan struct {
+	ZoneID  string
+	Creates []RRSet
+	Upserts []RRSet
+	Deletes []RRSet
+	Skipped []RRSet
+}
+
+// PlanZone diffs desired against the record sets currently in the zone.
+func (r53 *Route53) PlanZone(zoneID string, desired []RRSet, opts PlanOptions) (Plan, error) {
+	zone, err := r53.GetHostedZone(zoneID)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return zone.Plan(desired, opts)
+}
+
+// ApplyPlan submits the changes of a plan in batches.
+func (r53 *Route53) ApplyPlan(plan Plan, comment string) ([]ChangeInfo, error) {
+	zone := HostedZone{r53: r53, ID: plan.ZoneID}
+
+	return zone.ChangeRRSetBatches(plan.Changes(), comment)
+}
+
+// DiffRRSets works out the plan that turns current into desired for the zone
+// named zoneName. Desired must hold each name, type and set identifier only
+// once.
+func DiffRRSets(zoneName string, current, desired []RRSet, opts PlanOptions) (Plan, error) {
+	owns := opts.Owns
+	if owns == nil {
+		owns = func(rrset RRSet) bool {
+			return !isZoneApexRecord(rrset, zoneName) && rrset.TrafficPolicyInstanceID == ""
+		}
+	}
+
+	plan := Plan{
+		Creates: []RRSet{},
+		Upserts: []RRSet{},
+		Deletes: []RRSet{},
+		Skipped: []RRSet{},
+	}
+
+	existing := map[string]RRSet{}
+	for _, rrset := range current {
+		existing[rrsetKey(rrset)] = rrset
+	}
+
+	wanted := map[string]bool{}
+	for _, rrset := range desired {
+		key := rrsetKey(rrset)
+		if wanted[key] {
+			return Plan{}, fmt.Errorf("%s %s %q is in the desired state twice", rrset.Name, rrset.Type, rrset.SetIdentifier)
+		}
+		wanted[key] = true
+
+		have, ok := existing[key]
+		switch {
+		case !ok:
+			plan.Creates = append(plan.Creates, rrset)
+		case !owns(have):
+			plan.Skipped = append(plan.Skipped, rrset)
+		case !rrsetsEqual(have, rrset):
+			plan.Upserts = append(plan.Upserts, rrset)
+		}
+	}
+
+	if !opts.NoDelete {
+		for _, rrset := range current {
+			if !wanted[rrsetKey(rrset)] && owns(rrset) {
+				plan.Deletes = append(plan.Deletes, rrset)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// rrsetsEqual compares record sets the way Route53 does: names and alias
+// targets ignoring case and escapes, and values ignoring order.
+func rrsetsEqual(a, b RRSet) bool {
+	return reflect.DeepEqual(comparableRRSet(a), comparableRRSet(b))
+}
+
+func comparableRRSet(rrset RRSet) RRSet {
+	rrset.Name = rrsetKey(rrset)
+
+	if rrset.ResourceRecords != nil {
+		values := []string{}
+		for _, rr := range rrset.ResourceRecords.ResourceRecord {
+			values = append(values, rr.Value)
+		}
+		sort.Strings(values)
+
+		rrset.ResourceRecords = nil
+		if len(values) > 0 {
+			rrset.ResourceRecords = &ResourceRecords{}
+			for _, value := range values {
+				rrset.ResourceRecords.ResourceRecord = append(rrset.ResourceRecords.ResourceRecord, ResourceRecord{Value: value})
+			}
+		}
+	}
+
+	if rrset.AliasTarget != nil {
+		alias := *rrset.AliasTarget
+		alias.HostedZoneID = strings.Replace(alias.HostedZoneID, "/hostedzone/", "", -1)
+		alias.DNSName = CanonicalName(alias.DNSName)
+		rrset.AliasTarget = &alias
+	}
+
+	return rrset
+}
+
+// Changes returns the plan as record set changes. Deletes come first so a
+// name can change type (e.g. CNAME to A) within one batch.
+func (p Plan) Changes() []RRSetChange {
+	changes := []RRSetChange{}
+	for _, rrset := range p.Deletes {
+		changes = append(changes, RRSetChange{Action: "DELETE", RRSet: rrset})
+	}
+	for _, rrset := range p.Upserts {
+		changes = append(changes, RRSetChange{Action: "UPSERT", RRSet: rrset})
+	}
+	for _, rrset := range p.Creates {
+		changes = append(changes, RRSetChange{Action: "CREATE", RRSet: rrset})
+	}
+	return changes
+}
+
+func (p Plan) Empty() bool {
+	return len(p.Creates) == 0 && len(p.Upserts) == 0 && len(p.Deletes) == 0
+}
+
+// String lists the plan one record set per line, prefixed with + for
+// creates, ~ for upserts, - for deletes and ! for skipped record sets.
+func (p Plan) String() string {
+	lines := []string{}
+	add := func(prefix string, rrsets []RRSet) {
+		for _, rrset := range rrsets {
+			line := fmt.Sprintf("%s %s %s", prefix, rrset.Name, rrset.Type)
+			if rrset.SetIdentifier != "" {
+				line += fmt.Sprintf(" %q", rrset.SetIdentifier)
+			}
+			lines = append(lines, line)
+		}
+	}
+	add("-", p.Deletes)
+	add("~", p.Upserts)
+	add("+", p.Creates)
+	add("!", p.Skipped)
+
+	return strings.Join(lines, "\n")
+}
+
+// Convenience functions on AWS APIs.
+
+func (z *HostedZone) Plan(desired []RRSet, opts PlanOptions) (Plan, error) {
+	current, err := z.ListRRSets()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	if opts.Owns == nil && z.r53.Registry != nil {
+		opts.Owns = z.r53.Registry.OwnsFunc(current)
+	}
+
+	plan, err := DiffRRSets(z.Name, current, desired, opts)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.ZoneID = z.ID
+
+	return plan, nil
+}
@@ -0,0 +1,44 @@
+package route53
+
+import "testing"
+
+func TestDiffRRSets(t *testing.T) {
+	a := func(name, identifier, value string) RRSet {
+		return RRSet{Name: name, Type: "A", SetIdentifier: identifier, TTL: 60,
+			ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: value}}}}
+	}
+	current := []RRSet{
+		{Name: "example.com.", Type: "NS", TTL: 172800,
+			ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: "ns-1.example.net."}}}},
+		a("www.example.com.", "", "192.0.2.1"),
+		a("old.example.com.", "", "192.0.2.2"),
+	}
+	desired := []RRSet{
+		a("WWW.example.com", "", "192.0.2.9"),
+		a("new.example.com.", "", "192.0.2.3"),
+	}
+
+	plan, err := DiffRRSets("example.com.", current, desired, PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Creates) != 1 || len(plan.Upserts) != 1 || len(plan.Deletes) != 1 ||
+		plan.Deletes[0].Name != "old.example.com." {
+		t.Errorf("unexpected plan\n%s", plan)
+	}
+
+	for _, duplicate := range []RRSet{
+		a("new.example.com.", "", "192.0.2.4"),
+		a("NEW.example.com", "", "192.0.2.3"),
+	} {
+		if _, err := DiffRRSets("example.com.", current, append(desired, duplicate), PlanOptions{}); err == nil {
+			t.Errorf("duplicate %s: expected an error", duplicate.Name)
+		}
+	}
+
+	// Set identifiers tell record sets of the same name and type apart.
+	weighted := []RRSet{a("w.example.com.", "one", "192.0.2.5"), a("w.example.com.", "two", "192.0.2.6")}
+	if _, err := DiffRRSets("example.com.", current, weighted, PlanOptions{}); err != nil {
+		t.Error(err)
+	}
+}
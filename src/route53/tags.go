@@ -0,0 +1,179 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tags maps tag keys to values for a hosted zone or health check.
+type Tags map[string]string
+
+// XML RPC types.
+
+type Tag struct {
+	Key   string
+	Value string
+}
+
+type ChangeTagsForResourceRequest struct {
+	XMLName       xml.Name    `xml:"ChangeTagsForResourceRequest"`
+	XMLNS         string      `xml:"xmlns,attr"`
+	AddTags       *TagList    `xml:"AddTags,omitempty"`
+	RemoveTagKeys *TagKeyList `xml:"RemoveTagKeys,omitempty"`
+}
+
+type TagList struct {
+	Tag []Tag `xml:"Tag"`
+}
+
+type TagKeyList struct {
+	Key []string `xml:"Key"`
+}
+
+type ChangeTagsForResourceResponse struct {
+	XMLName xml.Name `xml:"ChangeTagsForResourceResponse"`
+}
+
+type ResourceTagSet struct {
+	ResourceType string
+	ResourceID   string `xml:"ResourceId"`
+	Tags         []Tag  `xml:"Tags>Tag"`
+}
+
+type ListTagsForResourceResponse struct {
+	XMLName        xml.Name `xml:"ListTagsForResourceResponse"`
+	ResourceTagSet ResourceTagSet
+}
+
+type ListTagsForResourcesRequest struct {
+	XMLName     xml.Name `xml:"ListTagsForResourcesRequest"`
+	XMLNS       string   `xml:"xmlns,attr"`
+	ResourceIDs []string `xml:"ResourceIds>ResourceId"`
+}
+
+type ListTagsForResourcesResponse struct {
+	XMLName         xml.Name         `xml:"ListTagsForResourcesResponse"`
+	ResourceTagSets []ResourceTagSet `xml:"ResourceTagSets>ResourceTagSet"`
+}
+
+// ListTagsForResources accepts at most this many IDs per call.
+const maxTagResources = 10
+
+func (t Tags) list() []Tag {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]Tag, 0, len(t))
+	for _, key := range keys {
+		tags = append(tags, Tag{Key: key, Value: t[key]})
+	}
+	return tags
+}
+
+func tagsFromList(list []Tag) Tags {
+	tags := Tags{}
+	for _, tag := range list {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+func tagResourceID(resourceType, id string) string {
+	return strings.Replace(id, "/"+resourceType+"/", "", -1)
+}
+
+// Route53 API requests.
+
+func (r53 *Route53) ChangeTagsForResource(resourceType, id string, add Tags, remove []string) error {
+	xmlReq := &ChangeTagsForResourceRequest{
+		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+	}
+	if len(add) > 0 {
+		xmlReq.AddTags = &TagList{Tag: add.list()}
+	}
+	if len(remove) > 0 {
+		xmlReq.RemoveTagKeys = &TagKeyList{Key: remove}
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/tags/%s/%s", resourceType, tagResourceID(resourceType, id)),
+		body:   xmlReq,
+	}
+
+	xmlRes := &ChangeTagsForResourceResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r53 *Route53) ListTagsForResource(resourceType, id string) (Tags, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/tags/%s/%s", resourceType, tagResourceID(resourceType, id)),
+	}
+
+	xmlRes := &ListTagsForResourceResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return Tags{}, err
+	}
+
+	return tagsFromList(xmlRes.ResourceTagSet.Tags), nil
+}
+
+// ListTagsForResources returns the tags of every resource, keyed by resource
+// ID, batching the IDs as Route53 requires.
+func (r53 *Route53) ListTagsForResources(resourceType string, ids []string) (map[string]Tags, error) {
+	tags := map[string]Tags{}
+
+	for start := 0; start < len(ids); start += maxTagResources {
+		end := start + maxTagResources
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		xmlReq := &ListTagsForResourcesRequest{
+			XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		}
+		for _, id := range ids[start:end] {
+			xmlReq.ResourceIDs = append(xmlReq.ResourceIDs, tagResourceID(resourceType, id))
+		}
+
+		req := request{
+			method: "POST",
+			path:   fmt.Sprintf("/2013-04-01/tags/%s", resourceType),
+			body:   xmlReq,
+		}
+
+		xmlRes := &ListTagsForResourcesResponse{}
+
+		if err := r53.run(req, xmlRes); err != nil {
+			return map[string]Tags{}, err
+		}
+
+		for _, set := range xmlRes.ResourceTagSets {
+			tags[set.ResourceID] = tagsFromList(set.Tags)
+		}
+	}
+
+	return tags, nil
+}
+
+// Convenience functions on AWS APIs.
+
+func (z *HostedZone) ListTags() (Tags, error) {
+	return z.r53.ListTagsForResource("hostedzone", z.ID)
+}
+
+func (z *HostedZone) ChangeTags(add Tags, remove []string) error {
+	return z.r53.ChangeTagsForResource("hostedzone", z.ID, add, remove)
+}
@@ -0,0 +1,101 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// XML RPC types.
+
+// DNSAnswer is what one of the zone's name servers would answer for a query,
+// as reported by TestDNSAnswer.
+type DNSAnswer struct {
+	XMLName      xml.Name `xml:"TestDNSAnswerResponse"`
+	Nameserver   string
+	RecordName   string
+	RecordType   string
+	RecordData   []string `xml:"RecordData>RecordDataEntry"`
+	ResponseCode string
+	Protocol     string
+}
+
+// Route53 API requests.
+
+// TestDNSAnswer asks Route53 what it would answer for name and rrtype in a
+// zone, which shows how latency, geolocation and weighted routing resolve.
+// resolverIP is the address of the resolver the query appears to come from,
+// and ednsSubnet the EDNS0 client subnet, either an address or a CIDR block.
+// Both may be empty.
+func (r53 *Route53) TestDNSAnswer(zoneID, name, rrtype, resolverIP, ednsSubnet string) (DNSAnswer, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/testdnsanswer",
+		params: &url.Values{
+			"hostedzoneid": []string{strings.Replace(zoneID, "/hostedzone/", "", -1)},
+			"recordname":   []string{name},
+			"recordtype":   []string{rrtype},
+		},
+	}
+
+	if resolverIP != "" {
+		if net.ParseIP(resolverIP) == nil {
+			return DNSAnswer{}, fmt.Errorf("invalid resolver IP %q", resolverIP)
+		}
+		req.params.Set("resolverip", resolverIP)
+	}
+
+	if ednsSubnet != "" {
+		if strings.Contains(ednsSubnet, "/") {
+			ip, subnet, err := net.ParseCIDR(ednsSubnet)
+			if err != nil {
+				return DNSAnswer{}, err
+			}
+			ones, _ := subnet.Mask.Size()
+			req.params.Set("edns0clientsubnetip", ip.String())
+			req.params.Set("edns0clientsubnetmask", strconv.Itoa(ones))
+		} else {
+			if net.ParseIP(ednsSubnet) == nil {
+				return DNSAnswer{}, fmt.Errorf("invalid EDNS0 client subnet %q", ednsSubnet)
+			}
+			req.params.Set("edns0clientsubnetip", ednsSubnet)
+		}
+	}
+
+	xmlRes := &DNSAnswer{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return DNSAnswer{}, err
+	}
+
+	return *xmlRes, nil
+}
+
+// Convenience functions on AWS APIs.
+
+func (z *HostedZone) TestDNSAnswer(name, rrtype, resolverIP, ednsSubnet string) (DNSAnswer, error) {
+	return z.r53.TestDNSAnswer(z.ID, AbsoluteName(name, z.Name), rrtype, resolverIP, ednsSubnet)
+}
+
+// Matches reports whether the answer holds exactly the given values, in any
+// order.
+func (a DNSAnswer) Matches(values []string) bool {
+	if len(a.RecordData) != len(values) {
+		return false
+	}
+
+	want := map[string]int{}
+	for _, value := range values {
+		want[value]++
+	}
+	for _, data := range a.RecordData {
+		if want[data] == 0 {
+			return false
+		}
+		want[data]--
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package route53
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// answerServer answers TestDNSAnswer with two A records, returning the query
+// of every request.
+func answerServer(t *testing.T) (*Route53, *[]url.Values) {
+	queries := []url.Values{}
+	r53 := handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "GET /2013-04-01/testdnsanswer" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+			return
+		}
+		queries = append(queries, r.URL.Query())
+
+		w.Write([]byte(`<TestDNSAnswerResponse><Nameserver>ns-1.example.net</Nameserver>
+			<RecordName>www.example.com.</RecordName><RecordType>A</RecordType>
+			<RecordData><RecordDataEntry>192.0.2.1</RecordDataEntry><RecordDataEntry>192.0.2.2</RecordDataEntry></RecordData>
+			<ResponseCode>NOERROR</ResponseCode><Protocol>UDP</Protocol></TestDNSAnswerResponse>`))
+	}))
+
+	return r53, &queries
+}
+
+func TestTestDNSAnswer(t *testing.T) {
+	r53, queries := answerServer(t)
+	zone := HostedZone{ID: "/hostedzone/Z1", Name: "example.com.", r53: r53}
+
+	tests := []struct {
+		resolverIP, ednsSubnet string
+		want                   url.Values
+	}{
+		{"", "", url.Values{}},
+		{"198.51.100.1", "", url.Values{"resolverip": {"198.51.100.1"}}},
+		{"2001:db8::53", "", url.Values{"resolverip": {"2001:db8::53"}}},
+		{"", "203.0.113.7", url.Values{"edns0clientsubnetip": {"203.0.113.7"}}},
+		{"", "203.0.113.0/24", url.Values{"edns0clientsubnetip": {"203.0.113.0"}, "edns0clientsubnetmask": {"24"}}},
+		{"", "2001:db8:1::/48", url.Values{"edns0clientsubnetip": {"2001:db8:1::"}, "edns0clientsubnetmask": {"48"}}},
+		{"198.51.100.1", "203.0.113.0/24", url.Values{"resolverip": {"198.51.100.1"},
+			"edns0clientsubnetip": {"203.0.113.0"}, "edns0clientsubnetmask": {"24"}}},
+	}
+
+	for i, test := range tests {
+		answer, err := zone.TestDNSAnswer("www", "A", test.resolverIP, test.ednsSubnet)
+		if err != nil {
+			t.Errorf("%q %q: %s", test.resolverIP, test.ednsSubnet, err)
+			continue
+		}
+		if answer.ResponseCode != "NOERROR" || len(answer.RecordData) != 2 {
+			t.Errorf("unexpected answer %+v", answer)
+		}
+
+		query := (*queries)[i]
+		if query.Get("hostedzoneid") != "Z1" || query.Get("recordname") != "www.example.com." || query.Get("recordtype") != "A" {
+			t.Errorf("%q %q: unexpected query %v", test.resolverIP, test.ednsSubnet, query)
+		}
+		for _, key := range []string{"resolverip", "edns0clientsubnetip", "edns0clientsubnetmask"} {
+			if query.Get(key) != test.want.Get(key) {
+				t.Errorf("%q %q: %s = %q, want %q", test.resolverIP, test.ednsSubnet, key, query.Get(key), test.want.Get(key))
+			}
+		}
+	}
+}
+
+func TestTestDNSAnswerInvalid(t *testing.T) {
+	r53, queries := answerServer(t)
+
+	for _, test := range [][2]string{
+		{"not-an-ip", ""},
+		{"198.51.100.256", ""},
+		{"", "203.0.113"},
+		{"", "203.0.113.0/33"},
+		{"", "example.com/24"},
+	} {
+		if _, err := r53.TestDNSAnswer("Z1", "www.example.com.", "A", test[0], test[1]); err == nil {
+			t.Errorf("%q %q: expected an error", test[0], test[1])
+		}
+	}
+	if len(*queries) != 0 {
+		t.Errorf("invalid addresses sent: %v", *queries)
+	}
+}
+
+func TestDNSAnswerMatches(t *testing.T) {
+	answer := DNSAnswer{RecordData: []string{"192.0.2.1", "192.0.2.2", "192.0.2.1"}}
+
+	tests := []struct {
+		values  []string
+		matches bool
+	}{
+		{[]string{"192.0.2.1", "192.0.2.2", "192.0.2.1"}, true},
+		{[]string{"192.0.2.2", "192.0.2.1", "192.0.2.1"}, true},
+		{[]string{"192.0.2.1", "192.0.2.2", "192.0.2.2"}, false},
+		{[]string{"192.0.2.1", "192.0.2.2"}, false},
+		{[]string{"192.0.2.1", "192.0.2.2", "192.0.2.1", "192.0.2.1"}, false},
+		{[]string{}, false},
+	}
+
+	for _, test := range tests {
+		if got := answer.Matches(test.values); got != test.matches {
+			t.Errorf("Matches(%v) = %v, want %v", test.values, got, test.matches)
+		}
+	}
+
+	if !(DNSAnswer{}).Matches(nil) {
+		t.Error("an empty answer should match no values")
+	}
+}
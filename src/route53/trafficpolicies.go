@@ -0,0 +1,499 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// XML RPC types.
+
+type TrafficPolicy struct {
+	ID       string `xml:"Id"`
+	Version  int
+	Name     string
+	Type     string
+	Document string
+	Comment  string
+}
+
+type TrafficPolicySummary struct {
+	ID                 string `xml:"Id"`
+	Name               string
+	Type               string
+	LatestVersion      int
+	TrafficPolicyCount int
+}
+
+// TrafficPolicyInstance is a traffic policy version applied to a name in a
+// hosted zone, which Route53 turns into record sets it manages itself.
+type TrafficPolicyInstance struct {
+	r53                  *Route53 `xml:"-"`
+	ID                   string   `xml:"Id"`
+	HostedZoneID         string   `xml:"HostedZoneId"`
+	Name                 string
+	TTL                  uint
+	State                string
+	Message              string
+	TrafficPolicyID      string `xml:"TrafficPolicyId"`
+	TrafficPolicyVersion int
+	TrafficPolicyType    string
+}
+
+type CreateTrafficPolicyRequest struct {
+	XMLName  xml.Name `xml:"CreateTrafficPolicyRequest"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Name     string
+	Document string
+	Comment  string `xml:",omitempty"`
+}
+
+type CreateTrafficPolicyResponse struct {
+	XMLName       xml.Name `xml:"CreateTrafficPolicyResponse"`
+	TrafficPolicy TrafficPolicy
+}
+
+type CreateTrafficPolicyVersionRequest struct {
+	XMLName  xml.Name `xml:"CreateTrafficPolicyVersionRequest"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Document string
+	Comment  string `xml:",omitempty"`
+}
+
+type CreateTrafficPolicyVersionResponse struct {
+	XMLName       xml.Name `xml:"CreateTrafficPolicyVersionResponse"`
+	TrafficPolicy TrafficPolicy
+}
+
+type GetTrafficPolicyResponse struct {
+	XMLName       xml.Name `xml:"GetTrafficPolicyResponse"`
+	TrafficPolicy TrafficPolicy
+}
+
+type ListTrafficPoliciesResponse struct {
+	XMLName               xml.Name               `xml:"ListTrafficPoliciesResponse"`
+	TrafficPolicies       []TrafficPolicySummary `xml:"TrafficPolicySummaries>TrafficPolicySummary"`
+	IsTruncated           bool
+	TrafficPolicyIDMarker string `xml:"TrafficPolicyIdMarker"`
+	MaxItems              uint
+}
+
+type ListTrafficPolicyVersionsResponse struct {
+	XMLName                    xml.Name        `xml:"ListTrafficPolicyVersionsResponse"`
+	TrafficPolicies            []TrafficPolicy `xml:"TrafficPolicies>TrafficPolicy"`
+	IsTruncated                bool
+	TrafficPolicyVersionMarker string
+	MaxItems                   uint
+}
+
+type UpdateTrafficPolicyCommentRequest struct {
+	XMLName xml.Name `xml:"UpdateTrafficPolicyCommentRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Comment string
+}
+
+type UpdateTrafficPolicyCommentResponse struct {
+	XMLName       xml.Name `xml:"UpdateTrafficPolicyCommentResponse"`
+	TrafficPolicy TrafficPolicy
+}
+
+type DeleteTrafficPolicyResponse struct {
+	XMLName xml.Name `xml:"DeleteTrafficPolicyResponse"`
+}
+
+type CreateTrafficPolicyInstanceRequest struct {
+	XMLName              xml.Name `xml:"CreateTrafficPolicyInstanceRequest"`
+	XMLNS                string   `xml:"xmlns,attr"`
+	HostedZoneID         string   `xml:"HostedZoneId"`
+	Name                 string
+	TTL                  uint
+	TrafficPolicyID      string `xml:"TrafficPolicyId"`
+	TrafficPolicyVersion int
+}
+
+type CreateTrafficPolicyInstanceResponse struct {
+	XMLName               xml.Name `xml:"CreateTrafficPolicyInstanceResponse"`
+	TrafficPolicyInstance TrafficPolicyInstance
+}
+
+type GetTrafficPolicyInstanceResponse struct {
+	XMLName               xml.Name `xml:"GetTrafficPolicyInstanceResponse"`
+	TrafficPolicyInstance TrafficPolicyInstance
+}
+
+type UpdateTrafficPolicyInstanceRequest struct {
+	XMLName              xml.Name `xml:"UpdateTrafficPolicyInstanceRequest"`
+	XMLNS                string   `xml:"xmlns,attr"`
+	TTL                  uint
+	TrafficPolicyID      string `xml:"TrafficPolicyId"`
+	TrafficPolicyVersion int
+}
+
+type UpdateTrafficPolicyInstanceResponse struct {
+	XMLName               xml.Name `xml:"UpdateTrafficPolicyInstanceResponse"`
+	TrafficPolicyInstance TrafficPolicyInstance
+}
+
+type DeleteTrafficPolicyInstanceResponse struct {
+	XMLName xml.Name `xml:"DeleteTrafficPolicyInstanceResponse"`
+}
+
+// ListTrafficPolicyInstancesResponse is returned by all three instance
+// listings; HostedZoneIdMarker is only set when listing across zones.
+type ListTrafficPolicyInstancesResponse struct {
+	XMLName                         xml.Name
+	TrafficPolicyInstances          []TrafficPolicyInstance `xml:"TrafficPolicyInstances>TrafficPolicyInstance"`
+	IsTruncated                     bool
+	HostedZoneIDMarker              string `xml:"HostedZoneIdMarker"`
+	TrafficPolicyInstanceNameMarker string
+	TrafficPolicyInstanceTypeMarker string
+	MaxItems                        uint
+}
+
+type GetTrafficPolicyInstanceCountResponse struct {
+	XMLName                    xml.Name `xml:"GetTrafficPolicyInstanceCountResponse"`
+	TrafficPolicyInstanceCount int
+}
+
+// Route53 API requests.
+
+func (r53 *Route53) CreateTrafficPolicy(name string, document TrafficPolicyDocument, comment string) (TrafficPolicy, error) {
+	xmlReq := &CreateTrafficPolicyRequest{
+		XMLNS:    "https://route53.amazonaws.com/doc/2013-04-01/",
+		Name:     name,
+		Document: document.String(),
+		Comment:  comment,
+	}
+
+	req := request{
+		method: "POST",
+		path:   "/2013-04-01/trafficpolicy",
+		body:   xmlReq,
+	}
+
+	xmlRes := &CreateTrafficPolicyResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicy{}, err
+	}
+
+	return xmlRes.TrafficPolicy, nil
+}
+
+// CreateTrafficPolicyVersion adds a new version of the document to an
+// existing policy. Instances keep using their version until updated.
+func (r53 *Route53) CreateTrafficPolicyVersion(id string, document TrafficPolicyDocument, comment string) (TrafficPolicy, error) {
+	xmlReq := &CreateTrafficPolicyVersionRequest{
+		XMLNS:    "https://route53.amazonaws.com/doc/2013-04-01/",
+		Document: document.String(),
+		Comment:  comment,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicy/%s", id),
+		body:   xmlReq,
+	}
+
+	xmlRes := &CreateTrafficPolicyVersionResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicy{}, err
+	}
+
+	return xmlRes.TrafficPolicy, nil
+}
+
+func (r53 *Route53) GetTrafficPolicy(id string, version int) (TrafficPolicy, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicy/%s/%d", id, version),
+	}
+
+	xmlRes := &GetTrafficPolicyResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicy{}, err
+	}
+
+	return xmlRes.TrafficPolicy, nil
+}
+
+func (r53 *Route53) ListTrafficPolicies() ([]TrafficPolicySummary, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/trafficpolicies",
+	}
+
+	xmlRes := &ListTrafficPoliciesResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return []TrafficPolicySummary{}, err
+	}
+	policies := xmlRes.TrafficPolicies
+
+	for xmlRes.IsTruncated {
+		req.params = &url.Values{
+			"trafficpolicyid": []string{xmlRes.TrafficPolicyIDMarker},
+		}
+
+		xmlRes = &ListTrafficPoliciesResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []TrafficPolicySummary{}, err
+		}
+		policies = append(policies, xmlRes.TrafficPolicies...)
+	}
+
+	return policies, nil
+}
+
+func (r53 *Route53) ListTrafficPolicyVersions(id string) ([]TrafficPolicy, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicies/%s/versions", id),
+	}
+
+	xmlRes := &ListTrafficPolicyVersionsResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return []TrafficPolicy{}, err
+	}
+	versions := xmlRes.TrafficPolicies
+
+	for xmlRes.IsTruncated {
+		req.params = &url.Values{
+			"trafficpolicyversion": []string{xmlRes.TrafficPolicyVersionMarker},
+		}
+
+		xmlRes = &ListTrafficPolicyVersionsResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []TrafficPolicy{}, err
+		}
+		versions = append(versions, xmlRes.TrafficPolicies...)
+	}
+
+	return versions, nil
+}
+
+func (r53 *Route53) UpdateTrafficPolicyComment(id string, version int, comment string) (TrafficPolicy, error) {
+	xmlReq := &UpdateTrafficPolicyCommentRequest{
+		XMLNS:   "https://route53.amazonaws.com/doc/2013-04-01/",
+		Comment: comment,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicy/%s/%d", id, version),
+		body:   xmlReq,
+	}
+
+	xmlRes := &UpdateTrafficPolicyCommentResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicy{}, err
+	}
+
+	return xmlRes.TrafficPolicy, nil
+}
+
+// DeleteTrafficPolicy deletes one version of a policy. Versions still used by
+// an instance cannot be deleted.
+func (r53 *Route53) DeleteTrafficPolicy(id string, version int) error {
+	req := request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicy/%s/%d", id, version),
+	}
+
+	xmlRes := &DeleteTrafficPolicyResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateTrafficPolicyInstance applies a policy version to name in a zone. The
+// instance is created asynchronously; its State is "Applied" once done.
+func (r53 *Route53) CreateTrafficPolicyInstance(zoneID, name string, ttl uint, policyID string, version int) (TrafficPolicyInstance, error) {
+	xmlReq := &CreateTrafficPolicyInstanceRequest{
+		XMLNS:                "https://route53.amazonaws.com/doc/2013-04-01/",
+		HostedZoneID:         strings.Replace(zoneID, "/hostedzone/", "", -1),
+		Name:                 name,
+		TTL:                  ttl,
+		TrafficPolicyID:      policyID,
+		TrafficPolicyVersion: version,
+	}
+
+	req := request{
+		method: "POST",
+		path:   "/2013-04-01/trafficpolicyinstance",
+		body:   xmlReq,
+	}
+
+	xmlRes := &CreateTrafficPolicyInstanceResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicyInstance{}, err
+	}
+	xmlRes.TrafficPolicyInstance.r53 = r53
+
+	return xmlRes.TrafficPolicyInstance, nil
+}
+
+func (r53 *Route53) GetTrafficPolicyInstance(id string) (TrafficPolicyInstance, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicyinstance/%s", id),
+	}
+
+	xmlRes := &GetTrafficPolicyInstanceResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicyInstance{}, err
+	}
+	xmlRes.TrafficPolicyInstance.r53 = r53
+
+	return xmlRes.TrafficPolicyInstance, nil
+}
+
+// UpdateTrafficPolicyInstance switches an instance to another policy version
+// and TTL, replacing the record sets it created.
+func (r53 *Route53) UpdateTrafficPolicyInstance(id string, ttl uint, policyID string, version int) (TrafficPolicyInstance, error) {
+	xmlReq := &UpdateTrafficPolicyInstanceRequest{
+		XMLNS:                "https://route53.amazonaws.com/doc/2013-04-01/",
+		TTL:                  ttl,
+		TrafficPolicyID:      policyID,
+		TrafficPolicyVersion: version,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicyinstance/%s", id),
+		body:   xmlReq,
+	}
+
+	xmlRes := &UpdateTrafficPolicyInstanceResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return TrafficPolicyInstance{}, err
+	}
+	xmlRes.TrafficPolicyInstance.r53 = r53
+
+	return xmlRes.TrafficPolicyInstance, nil
+}
+
+// DeleteTrafficPolicyInstance deletes an instance along with the record sets
+// Route53 created for it.
+func (r53 *Route53) DeleteTrafficPolicyInstance(id string) error {
+	req := request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/2013-04-01/trafficpolicyinstance/%s", id),
+	}
+
+	xmlRes := &DeleteTrafficPolicyInstanceResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListTrafficPolicyInstances lists the instances of every hosted zone.
+func (r53 *Route53) ListTrafficPolicyInstances() ([]TrafficPolicyInstance, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/trafficpolicyinstances",
+	}
+
+	return r53.listTrafficPolicyInstances(req)
+}
+
+func (r53 *Route53) ListTrafficPolicyInstancesByHostedZone(zoneID string) ([]TrafficPolicyInstance, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/trafficpolicyinstances/hostedzone",
+		params: &url.Values{
+			"id": []string{strings.Replace(zoneID, "/hostedzone/", "", -1)},
+		},
+	}
+
+	return r53.listTrafficPolicyInstances(req)
+}
+
+func (r53 *Route53) ListTrafficPolicyInstancesByPolicy(policyID string, version int) ([]TrafficPolicyInstance, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/trafficpolicyinstances/trafficpolicy",
+		params: &url.Values{
+			"id":      []string{policyID},
+			"version": []string{strconv.Itoa(version)},
+		},
+	}
+
+	return r53.listTrafficPolicyInstances(req)
+}
+
+func (r53 *Route53) listTrafficPolicyInstances(req request) ([]TrafficPolicyInstance, error) {
+	if req.params == nil {
+		req.params = &url.Values{}
+	}
+
+	instances := []TrafficPolicyInstance{}
+	for {
+		xmlRes := &ListTrafficPolicyInstancesResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []TrafficPolicyInstance{}, err
+		}
+		for i := range xmlRes.TrafficPolicyInstances {
+			xmlRes.TrafficPolicyInstances[i].r53 = r53
+		}
+		instances = append(instances, xmlRes.TrafficPolicyInstances...)
+
+		if !xmlRes.IsTruncated {
+			return instances, nil
+		}
+		if xmlRes.HostedZoneIDMarker != "" {
+			req.params.Set("hostedzoneid", xmlRes.HostedZoneIDMarker)
+		}
+		req.params.Set("trafficpolicyinstancename", xmlRes.TrafficPolicyInstanceNameMarker)
+		req.params.Set("trafficpolicyinstancetype", xmlRes.TrafficPolicyInstanceTypeMarker)
+	}
+}
+
+func (r53 *Route53) GetTrafficPolicyInstanceCount() (int, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/trafficpolicyinstancecount",
+	}
+
+	xmlRes := &GetTrafficPolicyInstanceCountResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return 0, err
+	}
+
+	return xmlRes.TrafficPolicyInstanceCount, nil
+}
+
+// Convenience functions on AWS APIs.
+
+// Policy parses the document of a traffic policy version.
+func (p TrafficPolicy) Policy() (TrafficPolicyDocument, error) {
+	return ParseTrafficPolicyDocument(p.Document)
+}
+
+func (i *TrafficPolicyInstance) Delete() error {
+	return i.r53.DeleteTrafficPolicyInstance(i.ID)
+}
+
+func (z *HostedZone) CreateTrafficPolicyInstance(name string, ttl uint, policyID string, version int) (TrafficPolicyInstance, error) {
+	return z.r53.CreateTrafficPolicyInstance(z.ID, AbsoluteName(name, z.Name), ttl, policyID, version)
+}
+
+func (z *HostedZone) ListTrafficPolicyInstances() ([]TrafficPolicyInstance, error) {
+	return z.r53.ListTrafficPolicyInstancesByHostedZone(z.ID)
+}
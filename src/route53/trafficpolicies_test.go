@@ -0,0 +1,220 @@
+package route53
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const policyZone = `<ListResourceRecordSetsResponse>
+	<ResourceRecordSets>
+		<ResourceRecordSet><Name>example.com.</Name><Type>NS</Type><TTL>172800</TTL>
+			<ResourceRecords><ResourceRecord><Value>ns-1.example.net.</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>plain.example.com.</Name><Type>A</Type><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		<ResourceRecordSet><Name>policy.example.com.</Name><Type>A</Type><SetIdentifier>a</SetIdentifier><Weight>1</Weight><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.2</Value></ResourceRecord></ResourceRecords>
+			<TrafficPolicyInstanceId>11111111-2222-3333-4444-555555555555</TrafficPolicyInstanceId></ResourceRecordSet>
+		<ResourceRecordSet><Name>policy.example.com.</Name><Type>A</Type><SetIdentifier>b</SetIdentifier><Weight>1</Weight><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.3</Value></ResourceRecord></ResourceRecords>
+			<TrafficPolicyInstanceId>11111111-2222-3333-4444-555555555555</TrafficPolicyInstanceId></ResourceRecordSet>
+	</ResourceRecordSets>
+	<IsTruncated>false</IsTruncated><MaxItems>100</MaxItems>
+</ListResourceRecordSetsResponse>`
+
+// In policyCopyZone plain.example.com. belongs to a traffic policy instance.
+const policyCopyZone = `<ListResourceRecordSetsResponse>
+	<ResourceRecordSets>
+		<ResourceRecordSet><Name>plain.example.com.</Name><Type>A</Type><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.4</Value></ResourceRecord></ResourceRecords>
+			<TrafficPolicyInstanceId>66666666-7777-8888-9999-000000000000</TrafficPolicyInstanceId></ResourceRecordSet>
+	</ResourceRecordSets>
+	<IsTruncated>false</IsTruncated><MaxItems>100</MaxItems>
+</ListResourceRecordSetsResponse>`
+
+// policyServer serves policyZone as zone Z1 and policyCopyZone as Z2.
+func policyServer(t *testing.T) (*Route53, *[]string) {
+	return recordingServer(t, map[string]string{
+		"GET /2013-04-01/hostedzone/Z1":        `<GetHostedZoneResponse><HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name></HostedZone></GetHostedZoneResponse>`,
+		"GET /2013-04-01/hostedzone/Z2":        `<GetHostedZoneResponse><HostedZone><Id>/hostedzone/Z2</Id><Name>example.com.</Name></HostedZone></GetHostedZoneResponse>`,
+		"GET /2013-04-01/hostedzone/Z1/rrset":  policyZone,
+		"GET /2013-04-01/hostedzone/Z2/rrset":  policyCopyZone,
+		"POST /2013-04-01/hostedzone/Z1/rrset": `<ChangeResourceRecordSetsResponse>` + testChangeInfo + `</ChangeResourceRecordSetsResponse>`,
+		"POST /2013-04-01/hostedzone/Z2/rrset": `<ChangeResourceRecordSetsResponse>` + testChangeInfo + `</ChangeResourceRecordSetsResponse>`,
+		"GET /2013-04-01/change/C1":            `<GetChangeResponse><ChangeInfo><Id>/change/C1</Id><Status>INSYNC</Status></ChangeInfo></GetChangeResponse>`,
+	})
+}
+
+func changeBodies(requests []string) string {
+	bodies := []string{}
+	for _, request := range requests {
+		if strings.HasPrefix(request, "POST ") {
+			bodies = append(bodies, request)
+		}
+	}
+	return strings.Join(bodies, "\n")
+}
+
+// instanceZoneServer serves listing as zone Z1. Once a traffic policy
+// instance is deleted, its record sets stay in the listing for lingering more
+// listings, as Route53 removes them in the background.
+func instanceZoneServer(t *testing.T, listing string, lingering int) (*Route53, *[]string) {
+	zone := ListRRSetResponse{}
+	if err := xml.Unmarshal([]byte(listing), &zone); err != nil {
+		t.Fatal(err)
+	}
+	remaining := ListRRSetResponse{}
+	for _, rrset := range zone.RRSets {
+		if !isTrafficPolicyRecord(rrset) {
+			remaining.RRSets = append(remaining.RRSets, rrset)
+		}
+	}
+
+	deleted := false
+	requests := []string{}
+	r53 := handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(data))
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2013-04-01/hostedzone/Z1":
+			w.Write([]byte(`<GetHostedZoneResponse><HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name></HostedZone></GetHostedZoneResponse>`))
+		case "GET /2013-04-01/hostedzone/Z1/rrset":
+			res := zone
+			if deleted && lingering == 0 {
+				res = remaining
+			} else if deleted {
+				lingering--
+			}
+			data, _ := xml.Marshal(res)
+			w.Write(data)
+		case "POST /2013-04-01/hostedzone/Z1/rrset":
+			w.Write([]byte(`<ChangeResourceRecordSetsResponse>` + testChangeInfo + `</ChangeResourceRecordSetsResponse>`))
+		case "GET /2013-04-01/change/C1":
+			w.Write([]byte(`<GetChangeResponse><ChangeInfo><Id>/change/C1</Id><Status>INSYNC</Status></ChangeInfo></GetChangeResponse>`))
+		case "DELETE /2013-04-01/trafficpolicyinstance/11111111-2222-3333-4444-555555555555":
+			deleted = true
+			w.Write([]byte(`<DeleteTrafficPolicyInstanceResponse/>`))
+		case "DELETE /2013-04-01/hostedzone/Z1":
+			w.Write([]byte(`<DeleteHostedZoneResponse>` + testChangeInfo + `</DeleteHostedZoneResponse>`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+
+	return r53, &requests
+}
+
+func TestDeleteHostedZoneRecursiveTrafficPolicies(t *testing.T) {
+	r53, requests := instanceZoneServer(t, policyZone, 2)
+
+	_, doomed, err := r53.DeleteHostedZoneRecursive("Z1", DeleteZoneOptions{PollEvery: time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doomed) != 3 {
+		t.Errorf("doomed %+v", doomed)
+	}
+
+	deletedInstance, deletedZone, listings := -1, -1, 0
+	for i, request := range *requests {
+		switch {
+		case strings.HasPrefix(request, "DELETE /2013-04-01/trafficpolicyinstance/"):
+			if deletedInstance >= 0 {
+				t.Error("instance deleted twice")
+			}
+			deletedInstance = i
+		case strings.HasPrefix(request, "DELETE /2013-04-01/hostedzone/Z1"):
+			deletedZone = i
+		case strings.HasPrefix(request, "GET /2013-04-01/hostedzone/Z1/rrset"):
+			listings++
+		}
+	}
+	if deletedInstance < 0 || deletedZone < deletedInstance {
+		t.Errorf("instance not deleted before the zone: %v", *requests)
+	}
+	// The first listing finds the record sets, then two more still show the
+	// instance's before one shows them gone.
+	if listings != 4 {
+		t.Errorf("zone listed %d times, want 4: %v", listings, *requests)
+	}
+
+	changes := changeBodies(*requests)
+	if !strings.Contains(changes, "plain.example.com.") || strings.Contains(changes, "policy.example.com.") {
+		t.Errorf("unexpected changes %s", changes)
+	}
+}
+
+func TestDeleteHostedZoneRecursiveOnlyInstanceRecords(t *testing.T) {
+	onlyInstance := strings.Replace(policyZone, `<ResourceRecordSet><Name>plain.example.com.</Name><Type>A</Type><TTL>60</TTL>
+			<ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>`, "", 1)
+	if onlyInstance == policyZone {
+		t.Fatal("plain.example.com. not removed from the listing")
+	}
+
+	r53, requests := instanceZoneServer(t, onlyInstance, 1)
+	if _, _, err := r53.DeleteHostedZoneRecursive("Z1", DeleteZoneOptions{PollEvery: time.Millisecond, Timeout: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	last := (*requests)[len(*requests)-1]
+	previous := (*requests)[len(*requests)-2]
+	if !strings.HasPrefix(last, "DELETE /2013-04-01/hostedzone/Z1") || !strings.HasPrefix(previous, "GET /2013-04-01/hostedzone/Z1/rrset") {
+		t.Errorf("zone not deleted once its record sets were gone: %v", *requests)
+	}
+
+	// Record sets that never go stop the zone from being deleted.
+	r53, requests = instanceZoneServer(t, onlyInstance, 1000000)
+	_, _, err := r53.DeleteHostedZoneRecursive("Z1", DeleteZoneOptions{PollEvery: time.Millisecond, Timeout: 20 * time.Millisecond})
+	if err == nil {
+		t.Error("expected a timeout")
+	}
+	if changes := changeBodies(*requests); changes != "" {
+		t.Errorf("unexpected changes %s", changes)
+	}
+	for _, request := range *requests {
+		if strings.HasPrefix(request, "DELETE /2013-04-01/hostedzone/Z1") {
+			t.Error("zone deleted with instance record sets left")
+		}
+	}
+}
+
+func TestCopyZoneSkipsTrafficPolicyRecords(t *testing.T) {
+	r53, requests := policyServer(t)
+
+	result, err := r53.CopyZone("Z1", "Z2", CopyZoneOptions{Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Copied) != 0 {
+		t.Errorf("copied %+v", result.Copied)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Name != "plain.example.com." {
+		t.Errorf("conflicts %+v", result.Conflicts)
+	}
+	if changes := changeBodies(*requests); changes != "" {
+		t.Errorf("traffic policy records changed: %s", changes)
+	}
+}
+
+func TestImportZoneFileSkipsTrafficPolicyRecords(t *testing.T) {
+	r53, _ := policyServer(t)
+	zone := HostedZone{ID: "/hostedzone/Z1", Name: "example.com.", r53: r53}
+
+	file := "$TTL 60\npolicy A 192.0.2.10\nplain A 192.0.2.11\nnew A 192.0.2.12\n"
+
+	changes, _, err := zone.ImportZoneFile(strings.NewReader(file), ImportZoneOptions{Overwrite: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, change := range changes {
+		names = append(names, change.Action+" "+change.RRSet.Name)
+	}
+	if strings.Join(names, ",") != "UPSERT plain.example.com.,UPSERT new.example.com." {
+		t.Errorf("changes %v", names)
+	}
+}
@@ -0,0 +1,176 @@
+package route53
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const TrafficPolicyFormatVersion = "2015-10-01"
+
+// TrafficPolicyDocument is the JSON document describing a traffic policy: a
+// tree of rules, starting at StartRule (or just StartEndpoint), whose leaves
+// are endpoints. Rules and endpoints are keyed by an ID of our choosing.
+type TrafficPolicyDocument struct {
+	AWSPolicyFormatVersion string
+	RecordType             string
+	StartEndpoint          string                           `json:",omitempty"`
+	StartRule              string                           `json:",omitempty"`
+	Endpoints              map[string]TrafficPolicyEndpoint `json:",omitempty"`
+	Rules                  map[string]TrafficPolicyRule     `json:",omitempty"`
+}
+
+// TrafficPolicyEndpoint is a value (an IP address, domain name...) or an AWS
+// resource such as "elastic-load-balancer", "cloudfront" or "s3-website".
+type TrafficPolicyEndpoint struct {
+	Type   string
+	Region string `json:",omitempty"`
+	Value  string
+}
+
+// TrafficPolicyRule routes to its targets by RuleType: "failover" uses
+// Primary and Secondary, "geo" uses Locations, "geoproximity" uses
+// GeoproximityLocations, "latency" uses Regions, and "weighted" and
+// "multivalue" use Items.
+type TrafficPolicyRule struct {
+	RuleType              string
+	Primary               *TrafficPolicyTarget                `json:",omitempty"`
+	Secondary             *TrafficPolicyTarget                `json:",omitempty"`
+	Locations             []TrafficPolicyLocation             `json:",omitempty"`
+	GeoproximityLocations []TrafficPolicyGeoproximityLocation `json:",omitempty"`
+	Regions               []TrafficPolicyRegion               `json:",omitempty"`
+	Items                 []TrafficPolicyItem                 `json:",omitempty"`
+}
+
+// TrafficPolicyTarget refers to either an endpoint or another rule.
+type TrafficPolicyTarget struct {
+	EndpointReference    string `json:",omitempty"`
+	RuleReference        string `json:",omitempty"`
+	EvaluateTargetHealth bool   `json:",omitempty"`
+	HealthCheck          string `json:",omitempty"`
+}
+
+type TrafficPolicyLocation struct {
+	TrafficPolicyTarget
+	IsDefault   bool   `json:",omitempty"`
+	Continent   string `json:",omitempty"`
+	Country     string `json:",omitempty"`
+	Subdivision string `json:",omitempty"`
+}
+
+type TrafficPolicyGeoproximityLocation struct {
+	TrafficPolicyTarget
+	Region    string `json:",omitempty"`
+	Latitude  string `json:",omitempty"`
+	Longitude string `json:",omitempty"`
+	Bias      int    `json:",omitempty"`
+}
+
+type TrafficPolicyRegion struct {
+	TrafficPolicyTarget
+	Region string
+}
+
+type TrafficPolicyItem struct {
+	TrafficPolicyTarget
+	Weight int `json:",omitempty"`
+}
+
+func ParseTrafficPolicyDocument(document string) (TrafficPolicyDocument, error) {
+	doc := TrafficPolicyDocument{}
+
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		return TrafficPolicyDocument{}, err
+	}
+
+	return doc, nil
+}
+
+// String returns the document as JSON, filling in the format version.
+func (d TrafficPolicyDocument) String() string {
+	if d.AWSPolicyFormatVersion == "" {
+		d.AWSPolicyFormatVersion = TrafficPolicyFormatVersion
+	}
+
+	data, _ := json.Marshal(d)
+	return string(data)
+}
+
+// Validate checks the document starts somewhere and that every reference
+// points at an endpoint or rule it defines.
+func (d TrafficPolicyDocument) Validate() error {
+	problems := []string{}
+
+	if d.RecordType == "" {
+		problems = append(problems, "no record type")
+	}
+	switch {
+	case d.StartEndpoint == "" && d.StartRule == "":
+		problems = append(problems, "needs a start endpoint or start rule")
+	case d.StartEndpoint != "" && d.StartRule != "":
+		problems = append(problems, "cannot have both a start endpoint and a start rule")
+	case d.StartEndpoint != "" && !d.hasEndpoint(d.StartEndpoint):
+		problems = append(problems, fmt.Sprintf("unknown start endpoint %q", d.StartEndpoint))
+	case d.StartRule != "" && !d.hasRule(d.StartRule):
+		problems = append(problems, fmt.Sprintf("unknown start rule %q", d.StartRule))
+	}
+
+	ids := []string{}
+	for id := range d.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		for _, target := range d.Rules[id].targets() {
+			switch {
+			case (target.EndpointReference == "") == (target.RuleReference == ""):
+				problems = append(problems, fmt.Sprintf("rule %q: targets need exactly one of an endpoint or rule reference", id))
+			case target.EndpointReference != "" && !d.hasEndpoint(target.EndpointReference):
+				problems = append(problems, fmt.Sprintf("rule %q: unknown endpoint %q", id, target.EndpointReference))
+			case target.RuleReference != "" && !d.hasRule(target.RuleReference):
+				problems = append(problems, fmt.Sprintf("rule %q: unknown rule %q", id, target.RuleReference))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid traffic policy document: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (d TrafficPolicyDocument) hasEndpoint(id string) bool {
+	_, ok := d.Endpoints[id]
+	return ok
+}
+
+func (d TrafficPolicyDocument) hasRule(id string) bool {
+	_, ok := d.Rules[id]
+	return ok
+}
+
+func (r TrafficPolicyRule) targets() []TrafficPolicyTarget {
+	targets := []TrafficPolicyTarget{}
+	if r.Primary != nil {
+		targets = append(targets, *r.Primary)
+	}
+	if r.Secondary != nil {
+		targets = append(targets, *r.Secondary)
+	}
+	for _, location := range r.Locations {
+		targets = append(targets, location.TrafficPolicyTarget)
+	}
+	for _, location := range r.GeoproximityLocations {
+		targets = append(targets, location.TrafficPolicyTarget)
+	}
+	for _, region := range r.Regions {
+		targets = append(targets, region.TrafficPolicyTarget)
+	}
+	for _, item := range r.Items {
+		targets = append(targets, item.TrafficPolicyTarget)
+	}
+	return targets
+}
@@ -0,0 +1,370 @@
+package route53
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists every problem found while validating record sets.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid record sets: " + strings.Join(e.Problems, "; ")
+}
+
+// Record types Route53 accepts in record sets.
+var supportedTypes = map[string]bool{
+	"A": true, "AAAA": true, "CAA": true, "CNAME": true, "DS": true, "MX": true, "NAPTR": true,
+	"NS": true, "PTR": true, "SOA": true, "SPF": true, "SRV": true, "TXT": true,
+}
+
+// Continent codes Route53 accepts in a GeoLocation.
+var continentCodes = map[string]bool{
+	"AF": true, "AN": true, "AS": true, "EU": true, "NA": true, "OC": true, "SA": true,
+}
+
+var healthCheckIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+const (
+	maxNameLength  = 253
+	maxLabelLength = 63
+	maxValueLength = 4000
+	maxTXTString   = 255
+
+	maxSetIdentifierLength = 128
+
+	maxMultiValueAnswers = 8
+)
+
+// Validate checks a record set for the mistakes Route53 would otherwise
+// reject with InvalidChangeBatch, returning all of them at once as a
+// *ValidationError.
+func (rrset RRSet) Validate() error {
+	problems := rrset.problems()
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidateRRSets validates record sets meant for the zone named zoneName,
+// both individually and together: names must be inside the zone, there can
+// be no CNAME at the apex, and a name with a CNAME can have no other records.
+func ValidateRRSets(zoneName string, rrsets []RRSet) error {
+	problems := []string{}
+	for _, rrset := range rrsets {
+		problems = append(problems, rrset.problems()...)
+	}
+	problems = append(problems, batchProblems(zoneName, rrsets)...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidateChanges validates a change batch for the zone named zoneName. The
+// record sets being deleted are only checked individually.
+func ValidateChanges(zoneName string, changes []RRSetChange) error {
+	problems := []string{}
+	kept := []RRSet{}
+	for _, change := range changes {
+		switch change.Action {
+		case "CREATE", "UPSERT":
+			kept = append(kept, change.RRSet)
+		case "DELETE":
+		default:
+			problems = append(problems, fmt.Sprintf("%s %s: unknown action %q", change.RRSet.Name, change.RRSet.Type, change.Action))
+		}
+		problems = append(problems, change.RRSet.problems()...)
+	}
+	problems = append(problems, batchProblems(zoneName, kept)...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func batchProblems(zoneName string, rrsets []RRSet) []string {
+	problems := []string{}
+	zone := CanonicalName(zoneName)
+
+	names := []string{}
+	types := map[string][]string{}
+	for _, rrset := range rrsets {
+		name := CanonicalName(rrset.Name)
+		if !IsSubdomain(name, zone) {
+			problems = append(problems, fmt.Sprintf("%s %s: not in zone %s", rrset.Name, rrset.Type, zoneName))
+		}
+		if rrset.Type == "CNAME" && name == zone {
+			problems = append(problems, fmt.Sprintf("%s CNAME: not allowed at the zone apex", rrset.Name))
+		}
+		if _, ok := types[name]; !ok {
+			names = append(names, name)
+		}
+		types[name] = append(types[name], rrset.Type)
+	}
+
+	for _, name := range names {
+		cname, other := false, false
+		for _, rrtype := range types[name] {
+			if rrtype == "CNAME" {
+				cname = true
+			} else {
+				other = true
+			}
+		}
+		if cname && other {
+			problems = append(problems, fmt.Sprintf("%s: CNAME cannot coexist with other records", name))
+		}
+	}
+
+	return problems
+}
+
+func (rrset RRSet) problems() []string {
+	problems := []string{}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("%s %s: ", rrset.Name, rrset.Type)+fmt.Sprintf(format, args...))
+	}
+
+	if err := validateName(rrset.Name); err != nil {
+		add("%s", err)
+	}
+
+	if !supportedTypes[rrset.Type] {
+		add("unsupported type")
+	}
+
+	// Routing policies
+	policies := 0
+	if rrset.Weight != nil {
+		policies++
+	}
+	if rrset.Failover != "" {
+		policies++
+		if rrset.Failover != "PRIMARY" && rrset.Failover != "SECONDARY" {
+			add("failover must be PRIMARY or SECONDARY")
+		}
+	}
+	if rrset.Region != "" {
+		policies++
+	}
+	if rrset.GeoLocation != nil {
+		policies++
+		if err := validateGeoLocation(*rrset.GeoLocation); err != nil {
+			add("%s", err)
+		}
+	}
+	if rrset.MultiValueAnswer {
+		policies++
+		if rrset.AliasTarget != nil {
+			add("multivalue answer records cannot be aliases")
+		}
+		if rrset.ResourceRecords != nil && len(rrset.ResourceRecords.ResourceRecord) > maxMultiValueAnswers {
+			add("multivalue answer records are answered with at most %d values", maxMultiValueAnswers)
+		}
+	}
+	if policies > 1 {
+		add("more than one routing policy")
+	}
+	if rrset.SetIdentifier == "" && policies > 0 {
+		add("routing policy requires a set identifier")
+	}
+	if rrset.SetIdentifier != "" && policies == 0 {
+		add("set identifier without a routing policy")
+	}
+	if len(rrset.SetIdentifier) > maxSetIdentifierLength {
+		add("set identifier longer than %d characters", maxSetIdentifierLength)
+	}
+
+	if rrset.HealthCheckID != "" && !healthCheckIDPattern.MatchString(rrset.HealthCheckID) {
+		add("malformed health check ID %q", rrset.HealthCheckID)
+	}
+
+	values := []string{}
+	if rrset.ResourceRecords != nil {
+		for _, rr := range rrset.ResourceRecords.ResourceRecord {
+			values = append(values, rr.Value)
+		}
+	}
+
+	if rrset.AliasTarget != nil {
+		if rrset.TTL != 0 {
+			add("alias records cannot have a TTL")
+		}
+		if len(values) > 0 {
+			add("alias records cannot have values")
+		}
+		if rrset.AliasTarget.HostedZoneID == "" || rrset.AliasTarget.DNSName == "" {
+			add("alias target needs a hosted zone ID and DNS name")
+		}
+		if rrset.Type == "NS" || rrset.Type == "SOA" {
+			add("%s records cannot be aliases", rrset.Type)
+		}
+		for _, problem := range aliasTargetProblems(rrset.Type, *rrset.AliasTarget) {
+			add("%s", problem)
+		}
+		return problems
+	}
+
+	if rrset.TTL == 0 {
+		add("TTL must be set")
+	}
+	if len(values) == 0 {
+		add("no values")
+	}
+	if rrset.Type == "CNAME" && len(values) > 1 {
+		add("CNAME can only have one value")
+	}
+	for _, value := range values {
+		if err := validateValue(rrset.Type, value); err != nil {
+			add("value %q: %s", value, err)
+		}
+	}
+
+	return problems
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+
+	name = strings.TrimSuffix(DecodeName(name), ".")
+	if len(name) > maxNameLength {
+		return fmt.Errorf("name longer than %d characters", maxNameLength)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return errors.New("empty label")
+		}
+		if len(label) > maxLabelLength {
+			return fmt.Errorf("label %q longer than %d characters", label, maxLabelLength)
+		}
+	}
+
+	return nil
+}
+
+func validateGeoLocation(location GeoLocation) error {
+	switch {
+	case location.ContinentCode == "" && location.CountryCode == "":
+		return errors.New("geolocation needs a continent or country")
+	case location.ContinentCode != "" && (location.CountryCode != "" || location.SubdivisionCode != ""):
+		return errors.New("geolocation cannot have both a continent and a country")
+	case location.ContinentCode != "" && !continentCodes[location.ContinentCode]:
+		return fmt.Errorf("unknown continent code %q", location.ContinentCode)
+	case location.CountryCode == "*" && location.SubdivisionCode != "":
+		return errors.New("the default geolocation cannot have a subdivision")
+	case location.CountryCode != "" && location.CountryCode != "*" && !isISOCode(location.CountryCode, 2, 2):
+		return fmt.Errorf("malformed country code %q", location.CountryCode)
+	case location.SubdivisionCode != "" && !isISOCode(location.SubdivisionCode, 1, 3):
+		return fmt.Errorf("malformed subdivision code %q", location.SubdivisionCode)
+	}
+
+	return nil
+}
+
+// isISOCode reports whether s is between min and max upper case letters or
+// digits, the shape of ISO 3166 codes.
+func isISOCode(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < 'A' || s[i] > 'Z') && !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateValue(rrtype, value string) error {
+	if len(value) > maxValueLength {
+		return fmt.Errorf("longer than %d characters", maxValueLength)
+	}
+
+	fields := strings.Fields(value)
+	uint16Fields := func(n int) error {
+		if len(fields) != n+1 {
+			return fmt.Errorf("expected %d fields", n+1)
+		}
+		for _, field := range fields[:n] {
+			if _, err := strconv.ParseUint(field, 10, 16); err != nil {
+				return fmt.Errorf("%q is not a number from 0 to 65535", field)
+			}
+		}
+		if fields[n] == "." {
+			return nil
+		}
+		return validateName(fields[n])
+	}
+
+	switch rrtype {
+	case "A":
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+			return errors.New("not an IPv4 address")
+		}
+	case "AAAA":
+		if ip := net.ParseIP(value); ip == nil || !strings.Contains(value, ":") {
+			return errors.New("not an IPv6 address")
+		}
+	case "CNAME", "NS", "PTR":
+		return validateName(value)
+	case "MX":
+		return uint16Fields(1)
+	case "SRV":
+		return uint16Fields(3)
+	case "TXT", "SPF":
+		return validateCharacterStrings(value)
+	}
+
+	return nil
+}
+
+// validateCharacterStrings checks a TXT value is made of quoted strings of at
+// most 255 characters each, counting a \DDD decimal escape as one.
+func validateCharacterStrings(value string) error {
+	rest := strings.TrimSpace(value)
+	if rest == "" {
+		return errors.New("empty")
+	}
+
+	for rest != "" {
+		if rest[0] != '"' {
+			return errors.New("strings must be quoted")
+		}
+
+		length, i := 0, 1
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] == '\\' {
+				i++
+				if i+2 < len(rest) && isDigit(rest[i]) && isDigit(rest[i+1]) && isDigit(rest[i+2]) {
+					if n, _ := strconv.Atoi(rest[i : i+3]); n > 255 {
+						return fmt.Errorf("escape \\%s out of range", rest[i:i+3])
+					}
+					i += 2
+				}
+			}
+			length++
+		}
+		if i >= len(rest) {
+			return errors.New("unterminated string")
+		}
+		if length > maxTXTString {
+			return fmt.Errorf("string longer than %d characters", maxTXTString)
+		}
+
+		rest = strings.TrimSpace(rest[i+1:])
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package route53
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRoutingPolicies(t *testing.T) {
+	values := &ResourceRecords{[]ResourceRecord{{Value: "192.0.2.1"}}}
+	tests := []struct {
+		rrset RRSet
+		valid bool
+	}{
+		{RRSet{Weight: weight(10), SetIdentifier: "x"}, true},
+		{RRSet{Weight: weight(0), SetIdentifier: "x"}, true},
+		{RRSet{Failover: "PRIMARY", SetIdentifier: "x"}, true},
+		{RRSet{Region: "us-east-1", SetIdentifier: "x"}, true},
+		{RRSet{MultiValueAnswer: true, SetIdentifier: "x"}, true},
+		{RRSet{GeoLocation: &GeoLocation{ContinentCode: "EU"}, SetIdentifier: "x"}, true},
+		{RRSet{Weight: weight(10), Failover: "PRIMARY", SetIdentifier: "x"}, false},
+		{RRSet{Weight: weight(10), Region: "us-east-1", SetIdentifier: "x"}, false},
+		{RRSet{Weight: weight(10), GeoLocation: &GeoLocation{ContinentCode: "EU"}, SetIdentifier: "x"}, false},
+		{RRSet{Weight: weight(10), MultiValueAnswer: true, SetIdentifier: "x"}, false},
+		{RRSet{Failover: "PRIMARY", Region: "us-east-1", SetIdentifier: "x"}, false},
+		{RRSet{Weight: weight(10)}, false},
+		{RRSet{Failover: "PRIMARY"}, false},
+		{RRSet{SetIdentifier: "x"}, false},
+	}
+
+	for _, test := range tests {
+		rrset := test.rrset
+		rrset.Name, rrset.Type, rrset.TTL, rrset.ResourceRecords = "www.example.com.", "A", 60, values
+
+		err := rrset.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%+v: %s", test.rrset, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%+v: expected an error", test.rrset)
+		}
+	}
+}
+
+// validRRSet is an A record set that passes validation.
+func validRRSet(name string) RRSet {
+	return RRSet{Name: name, Type: "A", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: "192.0.2.1"}}}}
+}
+
+func TestValidateNames(t *testing.T) {
+	label63 := strings.Repeat("a", 63)
+	// Too long with four 63 character labels; its last 254 characters,
+	// 253 without the trailing dot, are the longest name allowed.
+	long := strings.Repeat(label63+".", 4) + "example.com."
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"www.example.com.", true},
+		{"www.example.com", true},
+		{label63 + ".example.com.", true},
+		{strings.Repeat("a", 64) + ".example.com.", false},
+		{long[len(long)-254:], true},
+		{long, false},
+		{"a..example.com.", false},
+		{"", false},
+		{`a\056b.example.com.`, true},
+	}
+
+	for _, test := range tests {
+		err := validRRSet(test.name).Validate()
+		if test.valid && err != nil {
+			t.Errorf("%q: %s", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%q: expected an error", test.name)
+		}
+	}
+}
+
+func TestValidateTTL(t *testing.T) {
+	rrset := validRRSet("www.example.com.")
+	rrset.TTL = 0
+	if rrset.Validate() == nil {
+		t.Error("record without a TTL: expected an error")
+	}
+
+	alias := RRSet{Name: "www.example.com.", Type: "A",
+		AliasTarget: &AliasTarget{HostedZoneID: "Z1", DNSName: "lb.example.com."}}
+	if err := alias.Validate(); err != nil {
+		t.Errorf("alias: %s", err)
+	}
+	alias.TTL = 60
+	if alias.Validate() == nil {
+		t.Error("alias with a TTL: expected an error")
+	}
+}
+
+func TestValidateCNAMEs(t *testing.T) {
+	cname := func(name string) RRSet {
+		return RRSet{Name: name, Type: "CNAME", TTL: 60, ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: "target.example.net."}}}}
+	}
+	tests := []struct {
+		rrsets []RRSet
+		valid  bool
+	}{
+		{[]RRSet{cname("www.example.com."), validRRSet("api.example.com.")}, true},
+		{[]RRSet{cname("www.example.com."), validRRSet("WWW.example.com")}, false},
+		{[]RRSet{cname("example.com.")}, false},
+		{[]RRSet{cname("www.example.org.")}, false},
+	}
+
+	for i, test := range tests {
+		err := ValidateRRSets("example.com.", test.rrsets)
+		if test.valid && err != nil {
+			t.Errorf("%d: %s", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+	}
+
+	// Deleting the other record set makes room for the CNAME.
+	changes := []RRSetChange{
+		{Action: "DELETE", RRSet: validRRSet("www.example.com.")},
+		{Action: "CREATE", RRSet: cname("www.example.com.")},
+	}
+	if err := ValidateChanges("example.com.", changes); err != nil {
+		t.Error(err)
+	}
+	changes[0].Action = "UPSERT"
+	if ValidateChanges("example.com.", changes) == nil {
+		t.Error("CNAME next to an A record: expected an error")
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	tests := []struct {
+		rrtype, value string
+		valid         bool
+	}{
+		{"A", "192.0.2.1", true},
+		{"A", "192.0.2.256", false},
+		{"A", "::ffff:192.0.2.1", false},
+		{"A", "www.example.com.", false},
+		{"AAAA", "2001:db8::1", true},
+		{"AAAA", "192.0.2.1", false},
+		{"AAAA", "2001:db8::g", false},
+		{"MX", "10 mail.example.com.", true},
+		{"MX", "mail.example.com.", false},
+		{"MX", "65536 mail.example.com.", false},
+		{"MX", "10 mail..example.com.", false},
+		{"SRV", "10 5 5060 sip.example.com.", true},
+		{"SRV", "0 0 0 .", true},
+		{"SRV", "10 5 sip.example.com.", false},
+		{"SRV", "10 5 99999 sip.example.com.", false},
+		{"TXT", `"v=spf1 -all"`, true},
+		{"TXT", "v=spf1", false},
+	}
+
+	for _, test := range tests {
+		rrset := RRSet{Name: "www.example.com.", Type: test.rrtype, TTL: 60,
+			ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: test.value}}}}
+		err := rrset.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%s %q: %s", test.rrtype, test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s %q: expected an error", test.rrtype, test.value)
+		}
+	}
+}
+
+func TestValidateHealthCheckID(t *testing.T) {
+	for id, valid := range map[string]bool{
+		"abcdef01-2345-6789-abcd-ef0123456789": true,
+		"ABCDEF01-2345-6789-ABCD-EF0123456789": true,
+		"abcdef01-2345-6789-abcd-ef012345678":  false,
+		"web-1":                                false,
+	} {
+		rrset := validRRSet("www.example.com.")
+		rrset.HealthCheckID = id
+		if err := rrset.Validate(); (err == nil) != valid {
+			t.Errorf("%q: unexpected result %v", id, err)
+		}
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	rrsets := []RRSet{
+		{Name: "www.example.com.", Type: "MX", ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: "mail.example.com."}}}},
+		{Name: "www.example.org.", Type: "A", TTL: 60, SetIdentifier: "x",
+			ResourceRecords: &ResourceRecords{[]ResourceRecord{{Value: "192.0.2.1"}}}},
+	}
+
+	err := ValidateRRSets("example.com.", rrsets)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// No TTL and a bad MX value; a set identifier without a policy and
+	// outside the zone.
+	if len(verr.Problems) != 4 {
+		t.Errorf("%d problems, want 4: %v", len(verr.Problems), verr.Problems)
+	}
+}
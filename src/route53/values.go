@@ -0,0 +1,426 @@
+package route53
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// RecordValue is a typed resource record value. String returns the value in
+// the presentation format Route53 expects in ResourceRecord.Value.
+type RecordValue interface {
+	RecordType() string
+	String() string
+}
+
+type A struct {
+	IP net.IP
+}
+
+type AAAA struct {
+	IP net.IP
+}
+
+type CNAME struct {
+	Host string
+}
+
+type NS struct {
+	Host string
+}
+
+type PTR struct {
+	Host string
+}
+
+type MX struct {
+	Priority uint16
+	Host     string
+}
+
+type SRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// TXT holds the text of a record; it is split into as many 255 byte strings
+// as needed, and joined back up when parsed.
+type TXT struct {
+	Text string
+}
+
+type SPF struct {
+	Text string
+}
+
+type CAA struct {
+	Flags uint8
+	Tag   string
+	Value string
+}
+
+type NAPTR struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+func (v A) RecordType() string     { return "A" }
+func (v AAAA) RecordType() string  { return "AAAA" }
+func (v CNAME) RecordType() string { return "CNAME" }
+func (v NS) RecordType() string    { return "NS" }
+func (v PTR) RecordType() string   { return "PTR" }
+func (v MX) RecordType() string    { return "MX" }
+func (v SRV) RecordType() string   { return "SRV" }
+func (v TXT) RecordType() string   { return "TXT" }
+func (v SPF) RecordType() string   { return "SPF" }
+func (v CAA) RecordType() string   { return "CAA" }
+func (v NAPTR) RecordType() string { return "NAPTR" }
+
+// String returns "" if the address is missing or not IPv4.
+func (v A) String() string {
+	if ip := v.IP.To4(); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// String returns "" if the address is missing.
+func (v AAAA) String() string {
+	if ip := v.IP.To16(); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+func (v CNAME) String() string { return v.Host }
+func (v NS) String() string    { return v.Host }
+func (v PTR) String() string   { return v.Host }
+
+func (v MX) String() string {
+	return fmt.Sprintf("%d %s", v.Priority, v.Host)
+}
+
+func (v SRV) String() string {
+	return fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port, v.Target)
+}
+
+func (v TXT) String() string { return chunkCharacterStrings(v.Text) }
+func (v SPF) String() string { return chunkCharacterStrings(v.Text) }
+
+func (v CAA) String() string {
+	return fmt.Sprintf("%d %s %s", v.Flags, v.Tag, quoteCharacterString(v.Value))
+}
+
+func (v NAPTR) String() string {
+	return fmt.Sprintf("%d %d %s %s %s %s", v.Order, v.Preference,
+		quoteCharacterString(v.Flags), quoteCharacterString(v.Service), quoteCharacterString(v.Regexp), v.Replacement)
+}
+
+// NewRRSet builds a plain record set from typed values, which must all be of
+// the same type. A values need an IPv4 address and AAAA values an IPv6 one.
+func NewRRSet(name string, ttl uint, values ...RecordValue) (RRSet, error) {
+	if len(values) == 0 {
+		return RRSet{}, errors.New("no values")
+	}
+
+	rrset := RRSet{
+		Name:            name,
+		Type:            values[0].RecordType(),
+		TTL:             ttl,
+		ResourceRecords: &ResourceRecords{},
+	}
+	for _, value := range values {
+		if value.RecordType() != rrset.Type {
+			return RRSet{}, fmt.Errorf("mixed record types %s and %s", rrset.Type, value.RecordType())
+		}
+		if err := checkAddress(value); err != nil {
+			return RRSet{}, err
+		}
+		rrset.ResourceRecords.ResourceRecord = append(rrset.ResourceRecords.ResourceRecord, ResourceRecord{Value: value.String()})
+	}
+
+	return rrset, nil
+}
+
+// checkAddress rejects address values whose IP is missing or of the wrong
+// family, which would otherwise be written as "".
+func checkAddress(value RecordValue) error {
+	switch v := value.(type) {
+	case A:
+		if v.IP.To4() == nil {
+			return fmt.Errorf("A value %q is not an IPv4 address", v.IP)
+		}
+	case AAAA:
+		if v.IP.To16() == nil || v.IP.To4() != nil {
+			return fmt.Errorf("AAAA value %q is not an IPv6 address", v.IP)
+		}
+	}
+	return nil
+}
+
+// MultiValueEndpoint is one answer of a multivalue record, optionally taken
+// out of rotation by a health check.
+type MultiValueEndpoint struct {
+	Value         RecordValue
+	HealthCheckID string
+}
+
+// NewMultiValueRRSets builds one multivalue answer record set per endpoint,
+// identified by its value so the set identifiers stay stable between runs.
+// Values too long for a set identifier are identified by their SHA-1 hash
+// instead. Route53 answers each query with up to 8 of the healthy record
+// sets.
+func NewMultiValueRRSets(name string, ttl uint, endpoints ...MultiValueEndpoint) ([]RRSet, error) {
+	if len(endpoints) == 0 {
+		return []RRSet{}, errors.New("no endpoints")
+	}
+
+	rrsets := []RRSet{}
+	seen := map[string]bool{}
+	for _, endpoint := range endpoints {
+		rrset, err := NewRRSet(name, ttl, endpoint.Value)
+		if err != nil {
+			return []RRSet{}, err
+		}
+		if len(rrsets) > 0 && rrset.Type != rrsets[0].Type {
+			return []RRSet{}, fmt.Errorf("mixed record types %s and %s", rrsets[0].Type, rrset.Type)
+		}
+
+		value := endpoint.Value.String()
+		if seen[value] {
+			return []RRSet{}, fmt.Errorf("duplicate endpoint %s", value)
+		}
+		seen[value] = true
+
+		rrset.SetIdentifier = value
+		if len(value) > maxSetIdentifierLength {
+			sum := sha1.Sum([]byte(value))
+			rrset.SetIdentifier = hex.EncodeToString(sum[:])
+		}
+		rrset.MultiValueAnswer = true
+		rrset.HealthCheckID = endpoint.HealthCheckID
+		rrsets = append(rrsets, rrset)
+	}
+
+	return rrsets, nil
+}
+
+// Values parses the values of a record set into typed values.
+func (rrset RRSet) Values() ([]RecordValue, error) {
+	values := []RecordValue{}
+	if rrset.ResourceRecords == nil {
+		return values, nil
+	}
+
+	for _, rr := range rrset.ResourceRecords.ResourceRecord {
+		value, err := ParseRecordValue(rrset.Type, rr.Value)
+		if err != nil {
+			return []RecordValue{}, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// ParseRecordValue parses a ResourceRecord.Value of the given record type.
+func ParseRecordValue(rrtype, value string) (RecordValue, error) {
+	fields, err := splitRecordValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	need := func(n int) error {
+		if len(fields) != n {
+			return fmt.Errorf("%s value %q: expected %d fields", rrtype, value, n)
+		}
+		return nil
+	}
+	uint16Field := func(i int) (uint16, error) {
+		n, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("%s value %q: %q is not a number from 0 to 65535", rrtype, value, fields[i])
+		}
+		return uint16(n), nil
+	}
+
+	switch rrtype {
+	case "A", "AAAA":
+		if err := need(1); err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(fields[0])
+		if rrtype == "A" {
+			if ip == nil || ip.To4() == nil || strings.Contains(fields[0], ":") {
+				return nil, fmt.Errorf("A value %q: not an IPv4 address", value)
+			}
+			return A{IP: ip.To4()}, nil
+		}
+		if ip == nil || !strings.Contains(fields[0], ":") {
+			return nil, fmt.Errorf("AAAA value %q: not an IPv6 address", value)
+		}
+		return AAAA{IP: ip}, nil
+
+	case "CNAME", "NS", "PTR":
+		if err := need(1); err != nil {
+			return nil, err
+		}
+		switch rrtype {
+		case "CNAME":
+			return CNAME{Host: fields[0]}, nil
+		case "NS":
+			return NS{Host: fields[0]}, nil
+		}
+		return PTR{Host: fields[0]}, nil
+
+	case "MX":
+		if err := need(2); err != nil {
+			return nil, err
+		}
+		priority, err := uint16Field(0)
+		if err != nil {
+			return nil, err
+		}
+		return MX{Priority: priority, Host: fields[1]}, nil
+
+	case "SRV":
+		if err := need(4); err != nil {
+			return nil, err
+		}
+		numbers := [3]uint16{}
+		for i := range numbers {
+			if numbers[i], err = uint16Field(i); err != nil {
+				return nil, err
+			}
+		}
+		return SRV{Priority: numbers[0], Weight: numbers[1], Port: numbers[2], Target: fields[3]}, nil
+
+	case "TXT", "SPF":
+		if rrtype == "TXT" {
+			return TXT{Text: strings.Join(fields, "")}, nil
+		}
+		return SPF{Text: strings.Join(fields, "")}, nil
+
+	case "CAA":
+		if err := need(3); err != nil {
+			return nil, err
+		}
+		flags, err := strconv.ParseUint(fields[0], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("CAA value %q: %q is not a number from 0 to 255", value, fields[0])
+		}
+		return CAA{Flags: uint8(flags), Tag: fields[1], Value: fields[2]}, nil
+
+	case "NAPTR":
+		if err := need(6); err != nil {
+			return nil, err
+		}
+		order, err := uint16Field(0)
+		if err != nil {
+			return nil, err
+		}
+		preference, err := uint16Field(1)
+		if err != nil {
+			return nil, err
+		}
+		return NAPTR{
+			Order:       order,
+			Preference:  preference,
+			Flags:       fields[2],
+			Service:     fields[3],
+			Regexp:      fields[4],
+			Replacement: fields[5],
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported record type %s", rrtype)
+}
+
+// splitRecordValue splits a value into its whitespace separated fields,
+// unquoting and unescaping quoted strings. As in RFC 1035, \DDD is a
+// decimal escape.
+func splitRecordValue(value string) ([]string, error) {
+	fields := []string{}
+
+	for i := 0; i < len(value); {
+		switch c := value[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"':
+			field := []byte{}
+			j := i + 1
+			for ; j < len(value) && value[j] != '"'; j++ {
+				if value[j] != '\\' {
+					field = append(field, value[j])
+					continue
+				}
+				if j+3 < len(value) && isDigit(value[j+1]) && isDigit(value[j+2]) && isDigit(value[j+3]) {
+					n, err := strconv.ParseUint(value[j+1:j+4], 10, 8)
+					if err != nil {
+						return []string{}, fmt.Errorf("escape \\%s in %q is out of range", value[j+1:j+4], value)
+					}
+					field = append(field, byte(n))
+					j += 3
+					continue
+				}
+				if j+1 < len(value) {
+					j++
+					field = append(field, value[j])
+				}
+			}
+			if j >= len(value) {
+				return []string{}, fmt.Errorf("unterminated string in %q", value)
+			}
+			fields = append(fields, string(field))
+			i = j + 1
+		default:
+			j := i
+			for j < len(value) && value[j] != ' ' && value[j] != '\t' && value[j] != '"' {
+				j++
+			}
+			fields = append(fields, value[i:j])
+			i = j
+		}
+	}
+
+	return fields, nil
+}
+
+// quoteCharacterString quotes s for use in a value, escaping quotes and
+// backslashes, and using \DDD decimal escapes for non-printable bytes.
+func quoteCharacterString(s string) string {
+	quoted := []byte{'"'}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			quoted = append(quoted, '\\', c)
+		case c < ' ' || c >= 0x7f:
+			quoted = append(quoted, fmt.Sprintf("\\%03d", c)...)
+		default:
+			quoted = append(quoted, c)
+		}
+	}
+	return string(append(quoted, '"'))
+}
+
+// chunkCharacterStrings splits text into quoted strings of at most 255 bytes.
+func chunkCharacterStrings(text string) string {
+	chunks := []string{}
+	for len(text) > maxTXTString {
+		chunks = append(chunks, quoteCharacterString(text[:maxTXTString]))
+		text = text[maxTXTString:]
+	}
+	chunks = append(chunks, quoteCharacterString(text))
+
+	return strings.Join(chunks, " ")
+}
@@ -0,0 +1,152 @@
+package route53
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewRRSetAddresses(t *testing.T) {
+	tests := []struct {
+		value RecordValue
+		want  string
+	}{
+		{A{IP: net.ParseIP("192.0.2.1")}, "192.0.2.1"},
+		{A{IP: net.IPv4(192, 0, 2, 1).To4()}, "192.0.2.1"},
+		{AAAA{IP: net.ParseIP("2001:db8::1")}, "2001:db8::1"},
+		{A{IP: net.ParseIP("2001:db8::1")}, ""},
+		{A{}, ""},
+		{AAAA{IP: net.ParseIP("192.0.2.1")}, ""},
+		{AAAA{}, ""},
+	}
+
+	for _, test := range tests {
+		rrset, err := NewRRSet("www.example.com.", 60, test.value)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%s %#v: expected an error, got %+v", test.value.RecordType(), test.value, rrset.ResourceRecords)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %#v: %s", test.value.RecordType(), test.value, err)
+			continue
+		}
+		if got := rrset.ResourceRecords.ResourceRecord[0].Value; got != test.want {
+			t.Errorf("%s value %q, want %q", test.value.RecordType(), got, test.want)
+		}
+	}
+
+	if _, err := NewMultiValueRRSets("www.example.com.", 60, MultiValueEndpoint{Value: A{}}); err == nil {
+		t.Error("NewMultiValueRRSets: expected an error for an empty A value")
+	}
+}
+
+func TestAddressStringNil(t *testing.T) {
+	for _, value := range []RecordValue{A{}, AAAA{}, A{IP: net.ParseIP("2001:db8::1")}} {
+		if got := value.String(); got != "" {
+			t.Errorf("%s %#v: String() = %q, want \"\"", value.RecordType(), value, got)
+		}
+	}
+}
+
+func TestRecordValueRoundTrip(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	tests := []struct {
+		value RecordValue
+		want  string
+	}{
+		{MX{Priority: 10, Host: "mail.example.com."}, "10 mail.example.com."},
+		{SRV{Priority: 10, Weight: 5, Port: 5060, Target: "sip.example.com."}, "10 5 5060 sip.example.com."},
+		{CAA{Flags: 0, Tag: "issue", Value: "ca.example.net"}, `0 issue "ca.example.net"`},
+		{NAPTR{Order: 100, Preference: 10, Flags: "U", Service: "E2U+sip", Regexp: `!^.*$!sip:info@example.com!`, Replacement: "."},
+			`100 10 "U" "E2U+sip" "!^.*$!sip:info@example.com!" .`},
+		{TXT{Text: "v=spf1 -all"}, `"v=spf1 -all"`},
+		{TXT{Text: `say "hi" \ bye`}, `"say \"hi\" \\ bye"`},
+		{TXT{Text: "tab\there\x7f"}, `"tab\009here\127"`},
+		{TXT{Text: long}, `"` + long[:255] + `" "` + long[255:] + `"`},
+		{SPF{Text: "v=spf1 -all"}, `"v=spf1 -all"`},
+	}
+
+	for _, test := range tests {
+		if got := test.value.String(); got != test.want {
+			t.Errorf("%#v: String() = %q, want %q", test.value, got, test.want)
+			continue
+		}
+		parsed, err := ParseRecordValue(test.value.RecordType(), test.want)
+		if err != nil {
+			t.Errorf("%s %q: %s", test.value.RecordType(), test.want, err)
+			continue
+		}
+		if !reflect.DeepEqual(parsed, test.value) {
+			t.Errorf("%s %q parsed as %#v, want %#v", test.value.RecordType(), test.want, parsed, test.value)
+		}
+	}
+
+	// Strings are joined back up, and \DDD is decimal.
+	parsed, err := ParseRecordValue("TXT", `"a\066c" "def"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != (TXT{Text: "aBcdef"}) {
+		t.Errorf("unexpected TXT %#v", parsed)
+	}
+	if err := validateCharacterStrings(`"` + strings.Repeat(`\066`, 255) + `"`); err != nil {
+		t.Errorf("255 escapes: %s", err)
+	}
+}
+
+func TestParseRecordValueErrors(t *testing.T) {
+	tests := []struct {
+		rrtype, value string
+	}{
+		{"A", "2001:db8::1"},
+		{"A", "192.0.2.1 192.0.2.2"},
+		{"AAAA", "192.0.2.1"},
+		{"CNAME", "a.example.com. b.example.com."},
+		{"MX", "mail.example.com."},
+		{"MX", "-1 mail.example.com."},
+		{"SRV", "10 5 70000 sip.example.com."},
+		{"SRV", "10 5 5060"},
+		{"CAA", "256 issue \"ca.example.net\""},
+		{"CAA", "0 issue"},
+		{"NAPTR", `100 10 "U" "E2U+sip" .`},
+		{"NAPTR", `100 x "U" "E2U+sip" "!^.*$!sip:info@example.com!" .`},
+		{"TXT", `"unterminated`},
+		{"TXT", `"a\256b"`},
+		{"HINFO", `"cpu" "os"`},
+	}
+
+	for _, test := range tests {
+		if value, err := ParseRecordValue(test.rrtype, test.value); err == nil {
+			t.Errorf("%s %q: expected an error, got %#v", test.rrtype, test.value, value)
+		}
+	}
+}
+
+func TestNewMultiValueRRSetIdentifiers(t *testing.T) {
+	long := TXT{Text: strings.Repeat("a", 200)}
+	rrsets, err := NewMultiValueRRSets("txt.example.com.", 60,
+		MultiValueEndpoint{Value: TXT{Text: "short"}}, MultiValueEndpoint{Value: long})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rrsets[0].SetIdentifier != `"short"` {
+		t.Errorf("short value identified as %q", rrsets[0].SetIdentifier)
+	}
+	if id := rrsets[1].SetIdentifier; len(id) > maxSetIdentifierLength || strings.HasPrefix(long.String(), id) {
+		t.Errorf("long value identified as %q", id)
+	}
+	for _, rrset := range rrsets {
+		if err := rrset.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	again, _ := NewMultiValueRRSets("txt.example.com.", 60, MultiValueEndpoint{Value: long})
+	if again[0].SetIdentifier != rrsets[1].SetIdentifier {
+		t.Error("set identifier of a long value is not stable")
+	}
+}
@@ -0,0 +1,167 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// WaitOptions controls WaitForChanges.
+type WaitOptions struct {
+	// Time between starting two GetChange calls, shared by all the changes
+	// being waited for so a long list doesn't exceed the API rate limit.
+	// Defaults to one second.
+	Interval time.Duration
+
+	// How many GetChange calls may be in flight at once, so a slow response
+	// doesn't hold up the others. Defaults to 4.
+	Concurrency int
+
+	// Called after each change settles, with the number of changes settled
+	// so far out of the total. Changes still pending when ctx is done settle
+	// with its error.
+	Progress func(change ChangeResult, done, total int)
+}
+
+const defaultWaitConcurrency = 4
+
+// ChangeResult is the final state of one change, or the error that stopped
+// it from being polled.
+type ChangeResult struct {
+	ChangeInfo
+	Err error
+}
+
+// WaitForChanges polls changes until they are all INSYNC or ctx is done.
+// Changes with the same ID are only polled once. Pending changes are polled
+// in turn, starting one GetChange per interval with up to opts.Concurrency in
+// flight, so with n changes pending each one is checked about every n
+// intervals. Throttling, server errors and timeouts leave the change pending
+// to be tried again on its next turn; other errors fail it. The results are
+// in the order the IDs first appear; the error is set if any change failed to
+// sync.
+func (r53 *Route53) WaitForChanges(ctx context.Context, changes []ChangeInfo, opts WaitOptions) ([]ChangeResult, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultWaitConcurrency
+	}
+
+	results := []ChangeResult{}
+	pending := []int{}
+	seen := map[string]bool{}
+	done := 0
+
+	settle := func(i int) {
+		done++
+		if opts.Progress != nil {
+			opts.Progress(results[i], done, len(results))
+		}
+	}
+
+	for _, change := range changes {
+		id := strings.Replace(change.ID, "/change/", "", -1)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		results = append(results, ChangeResult{ChangeInfo: change})
+		if change.Status != "INSYNC" {
+			pending = append(pending, len(results)-1)
+		}
+	}
+	for i := range results {
+		if results[i].Status == "INSYNC" {
+			settle(i)
+		}
+	}
+
+	type poll struct {
+		i      int
+		change ChangeInfo
+		err    error
+	}
+	// Buffered so polls still in flight when ctx is done can finish.
+	polls := make(chan poll, concurrency)
+	inFlight := 0
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for len(pending) > 0 || inFlight > 0 {
+		tick := ticker.C
+		if len(pending) == 0 || inFlight == concurrency {
+			tick = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			for i := range results {
+				if results[i].Err == nil && results[i].Status != "INSYNC" {
+					results[i].Err = ctx.Err()
+					settle(i)
+				}
+			}
+			return results, ctx.Err()
+
+		case <-tick:
+			i := pending[0]
+			pending = pending[1:]
+			inFlight++
+			go func(i int, id string) {
+				change, err := r53.GetChange(id)
+				polls <- poll{i, change, err}
+			}(i, results[i].ID)
+
+		case p := <-polls:
+			inFlight--
+			switch {
+			case p.err != nil && isRetryable(p.err):
+				pending = append(pending, p.i)
+			case p.err != nil:
+				results[p.i].Err = p.err
+				settle(p.i)
+			case p.change.Status == "INSYNC":
+				results[p.i].ChangeInfo = p.change
+				settle(p.i)
+			default:
+				results[p.i].ChangeInfo = p.change
+				pending = append(pending, p.i)
+			}
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d changes failed to sync", failed, len(results))
+	}
+
+	return results, nil
+}
+
+// isRetryable reports whether a request that failed with err may succeed if
+// made again later.
+func isRetryable(err error) bool {
+	if e, ok := err.(*Error); ok {
+		switch e.Code {
+		case "Throttling", "PriorRequestNotComplete", "ServiceUnavailable":
+			return true
+		}
+		return e.StatusCode >= 500
+	}
+	if e, ok := err.(net.Error); ok {
+		return e.Timeout()
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitServer answers GetChange for each change ID with the statuses given,
+// in turn, repeating the last one. A status is either INSYNC, PENDING or an
+// HTTP status code and error code such as "400 Throttling".
+func waitServer(t *testing.T, statuses map[string][]string) *Route53 {
+	calls := map[string]int{}
+	var mu sync.Mutex
+	return handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id := strings.TrimPrefix(r.URL.Path, "/2013-04-01/change/")
+		replies, ok := statuses[id]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+			return
+		}
+
+		n := calls[id]
+		if n >= len(replies) {
+			n = len(replies) - 1
+		}
+		calls[id]++
+
+		var code int
+		var errorCode string
+		if _, err := fmt.Sscanf(replies[n], "%d %s", &code, &errorCode); err == nil {
+			w.WriteHeader(code)
+			fmt.Fprintf(w, `<ErrorResponse><Error><Code>%s</Code><Message>canned</Message></Error></ErrorResponse>`, errorCode)
+			return
+		}
+		fmt.Fprintf(w, `<GetChangeResponse><ChangeInfo><Id>/change/%s</Id><Status>%s</Status></ChangeInfo></GetChangeResponse>`, id, replies[n])
+	}))
+}
+
+func pendingChanges(ids ...string) []ChangeInfo {
+	changes := []ChangeInfo{}
+	for _, id := range ids {
+		changes = append(changes, ChangeInfo{ID: "/change/" + id, Status: "PENDING"})
+	}
+	return changes
+}
+
+func TestWaitForChangesRetries(t *testing.T) {
+	r53 := waitServer(t, map[string][]string{
+		"C1": {"400 Throttling", "PENDING", "400 Throttling", "INSYNC"},
+		"C2": {"400 PriorRequestNotComplete", "503 ServiceUnavailable", "500 InternalFailure", "INSYNC"},
+		"C3": {"INSYNC"},
+	})
+
+	progress := 0
+	opts := WaitOptions{
+		Interval: time.Millisecond,
+		Progress: func(change ChangeResult, done, total int) { progress++ },
+	}
+	results, err := r53.WaitForChanges(context.Background(), pendingChanges("C1", "C2", "C3", "C1"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || progress != 3 {
+		t.Fatalf("%d results, %d progress calls", len(results), progress)
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Status != "INSYNC" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+}
+
+func TestWaitForChangesFails(t *testing.T) {
+	r53 := waitServer(t, map[string][]string{
+		"C1": {"INSYNC"},
+		"C2": {"400 Throttling", "404 NoSuchChange"},
+		"C3": {"PENDING"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	progress := []ChangeResult{}
+	opts := WaitOptions{
+		Interval: time.Millisecond,
+		Progress: func(change ChangeResult, done, total int) {
+			progress = append(progress, change)
+			if done != len(progress) || total != 3 {
+				t.Errorf("progress %d of %d after %d calls", done, total, len(progress))
+			}
+		},
+	}
+	results, err := r53.WaitForChanges(ctx, pendingChanges("C1", "C2", "C3"), opts)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// The change still pending when ctx expired is reported too.
+	if len(progress) != 3 || progress[2].ID != "/change/C3" || progress[2].Err != context.DeadlineExceeded {
+		t.Errorf("unexpected progress %+v", progress)
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("C1: %s", results[0].Err)
+	}
+	if e, ok := results[1].Err.(*Error); !ok || e.Code != "NoSuchChange" || e.StatusCode != 404 {
+		t.Errorf("C2: unexpected error %#v", results[1].Err)
+	}
+	if results[2].Err != context.DeadlineExceeded {
+		t.Errorf("C3: unexpected error %v", results[2].Err)
+	}
+}
+
+func TestWaitForChangesConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, most := 0, 0
+	r53 := handlerServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		id := strings.TrimPrefix(r.URL.Path, "/2013-04-01/change/")
+		fmt.Fprintf(w, `<GetChangeResponse><ChangeInfo><Id>/change/%s</Id><Status>INSYNC</Status></ChangeInfo></GetChangeResponse>`, id)
+	}))
+
+	opts := WaitOptions{Interval: time.Millisecond, Concurrency: 2}
+	results, err := r53.WaitForChanges(context.Background(), pendingChanges("C1", "C2", "C3", "C4", "C5"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 5 {
+		t.Errorf("%d results", len(results))
+	}
+	if most != 2 {
+		t.Errorf("%d polls in flight at once, want 2", most)
+	}
+}
@@ -0,0 +1,219 @@
+package route53
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ZoneDefinition is the desired state of a zone as read from a definition
+// file, written in JSON:
+//
+//	{
+//	  "zone": "example.com.",
+//	  "ttl": 300,
+//	  "records": [
+//	    {"name": "@", "type": "MX", "values": ["10 mail.example.com."]},
+//	    {"name": "www", "type": "A", "ttl": 60, "values": ["192.0.2.1"],
+//	     "setIdentifier": "primary", "failover": "PRIMARY", "healthCheck": "web-1"},
+//	    {"name": "www", "type": "A",
+//	     "setIdentifier": "secondary", "failover": "SECONDARY",
+//	     "alias": {"zoneId": "Z2FDTNDATAQYW2", "dnsName": "d111111abcdef8.cloudfront.net."}},
+//	    {"name": "api", "type": "A", "values": ["192.0.2.10"],
+//	     "setIdentifier": "eu", "geoLocation": {"continent": "EU"}}
+//	  ]
+//	}
+//
+// The top level keys are zone, zoneId, private, ttl and records. Records take
+// name, type, ttl, values, setIdentifier, weight, failover, region,
+// multiValue, healthCheck, geoLocation (continent, country, subdivision) and
+// alias (zoneId, dnsName, evaluateTargetHealth). Unknown keys are an error.
+//
+// Record names are relative to the zone unless they end in a dot, and "@" is
+// the zone apex. The zone is looked up by name unless zoneId is given. TTL
+// defaults to the definition's ttl for records that are not aliases. A
+// record with a setIdentifier and no other routing policy is weighted and
+// needs a weight, which may be 0. Health checks are referred to by their Name
+// tag.
+type ZoneDefinition struct {
+	Zone    string             `json:"zone"`
+	ZoneID  string             `json:"zoneId"`
+	Private bool               `json:"private"`
+	TTL     uint               `json:"ttl"`
+	Records []RecordDefinition `json:"records"`
+}
+
+type RecordDefinition struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	TTL    uint     `json:"ttl"`
+	Values []string `json:"values"`
+
+	// Routing
+	SetIdentifier string `json:"setIdentifier"`
+	Weight        *uint8 `json:"weight"`
+	Failover      string `json:"failover"`
+	Region        string `json:"region"`
+	MultiValue    bool   `json:"multiValue"`
+	HealthCheck   string `json:"healthCheck"`
+
+	GeoLocation *GeoLocationDefinition `json:"geoLocation"`
+	Alias       *AliasDefinition       `json:"alias"`
+}
+
+// GeoLocationDefinition takes a continent code, or a country code ("*" for
+// the default location) with an optional subdivision code.
+type GeoLocationDefinition struct {
+	Continent   string `json:"continent"`
+	Country     string `json:"country"`
+	Subdivision string `json:"subdivision"`
+}
+
+type AliasDefinition struct {
+	ZoneID               string `json:"zoneId"`
+	DNSName              string `json:"dnsName"`
+	EvaluateTargetHealth bool   `json:"evaluateTargetHealth"`
+}
+
+// ReadZoneDefinitionFile reads a JSON zone definition from path.
+func ReadZoneDefinitionFile(path string) (ZoneDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ZoneDefinition{}, err
+	}
+	defer f.Close()
+
+	return ParseZoneDefinition(f)
+}
+
+// ParseZoneDefinition parses a JSON zone definition.
+func ParseZoneDefinition(r io.Reader) (ZoneDefinition, error) {
+	def := ZoneDefinition{}
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&def); err != nil {
+		return ZoneDefinition{}, err
+	}
+
+	return def, nil
+}
+
+// HealthChecks returns the names of the health checks the definition refers to.
+func (d ZoneDefinition) HealthChecks() []string {
+	names := []string{}
+	for _, record := range d.Records {
+		if record.HealthCheck != "" {
+			names = append(names, record.HealthCheck)
+		}
+	}
+	return names
+}
+
+// RRSets turns the definition into record sets for the zone named zoneName.
+// healthChecks maps health check names to IDs.
+func (d ZoneDefinition) RRSets(zoneName string, healthChecks map[string]string) ([]RRSet, error) {
+	rrsets := []RRSet{}
+	for i, record := range d.Records {
+		rrset := RRSet{
+			Name:          AbsoluteName(record.Name, zoneName),
+			Type:          strings.ToUpper(record.Type),
+			SetIdentifier: record.SetIdentifier,
+			Weight:        record.Weight,
+			Failover:      record.Failover,
+			Region:        record.Region,
+
+			MultiValueAnswer: record.MultiValue,
+		}
+
+		weighted := record.SetIdentifier != "" && record.Failover == "" && record.Region == "" &&
+			!record.MultiValue && record.GeoLocation == nil
+		if weighted && record.Weight == nil {
+			return []RRSet{}, fmt.Errorf("record %d (%s): weighted records need a weight", i, record.Name)
+		}
+
+		if record.GeoLocation != nil {
+			rrset.GeoLocation = &GeoLocation{
+				ContinentCode:   strings.ToUpper(record.GeoLocation.Continent),
+				CountryCode:     strings.ToUpper(record.GeoLocation.Country),
+				SubdivisionCode: strings.ToUpper(record.GeoLocation.Subdivision),
+			}
+		}
+
+		if record.HealthCheck != "" {
+			id, ok := healthChecks[record.HealthCheck]
+			if !ok {
+				return []RRSet{}, fmt.Errorf("record %d (%s): unknown health check %q", i, record.Name, record.HealthCheck)
+			}
+			rrset.HealthCheckID = id
+		}
+
+		if record.Alias != nil {
+			if len(record.Values) > 0 || record.TTL != 0 {
+				return []RRSet{}, fmt.Errorf("record %d (%s): alias records take no values or TTL", i, record.Name)
+			}
+			rrset.AliasTarget = &AliasTarget{
+				HostedZoneID:         record.Alias.ZoneID,
+				DNSName:              record.Alias.DNSName,
+				EvaluateTargetHealth: record.Alias.EvaluateTargetHealth,
+			}
+			rrsets = append(rrsets, rrset)
+			continue
+		}
+
+		if len(record.Values) == 0 {
+			return []RRSet{}, fmt.Errorf("record %d (%s): no values", i, record.Name)
+		}
+		rrset.TTL = record.TTL
+		if rrset.TTL == 0 {
+			rrset.TTL = d.TTL
+		}
+		rrset.ResourceRecords = &ResourceRecords{}
+		for _, value := range record.Values {
+			rrset.ResourceRecords.ResourceRecord = append(rrset.ResourceRecords.ResourceRecord, ResourceRecord{Value: value})
+		}
+
+		rrsets = append(rrsets, rrset)
+	}
+
+	return rrsets, nil
+}
+
+// HealthCheckIDsByName maps the Name tags of the account's health checks to
+// their IDs. Two checks with the same name are an error.
+func (r53 *Route53) HealthCheckIDsByName() (map[string]string, error) {
+	checks, err := r53.ListHealthChecks()
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	ids := []string{}
+	for _, check := range checks {
+		ids = append(ids, check.ID)
+	}
+
+	tags, err := r53.ListTagsForResources("healthcheck", ids)
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	// Sorted so the error names the same pair of checks every time.
+	sort.Strings(ids)
+
+	byName := map[string]string{}
+	for _, id := range ids {
+		name, ok := tags[id]["Name"]
+		if !ok {
+			continue
+		}
+		if other, ok := byName[name]; ok {
+			return map[string]string{}, fmt.Errorf("health checks %s and %s are both named %q", other, id, name)
+		}
+		byName[name] = id
+	}
+
+	return byName, nil
+}
@@ -0,0 +1,147 @@
+package route53
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testZoneJSON = `{
+  "zone": "example.com.",
+  "ttl": 300,
+  "records": [
+    {"name": "@", "type": "MX", "values": ["10 mail.example.com."]},
+    {"name": "www", "type": "A", "ttl": 60, "values": ["192.0.2.1"],
+     "setIdentifier": "primary", "failover": "PRIMARY", "healthCheck": "web-1"},
+    {"name": "www", "type": "A",
+     "setIdentifier": "secondary", "failover": "SECONDARY",
+     "alias": {"zoneId": "Z2FDTNDATAQYW2", "dnsName": "d111111abcdef8.cloudfront.net."}},
+    {"name": "api", "type": "A", "values": ["192.0.2.10"],
+     "setIdentifier": "eu", "geoLocation": {"continent": "EU"}}
+  ]
+}`
+
+func TestParseZoneDefinition(t *testing.T) {
+	def, err := ParseZoneDefinition(strings.NewReader(testZoneJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Zone != "example.com." || len(def.Records) != 4 || def.Records[2].Alias == nil {
+		t.Errorf("unexpected definition %+v", def)
+	}
+
+	if _, err := ParseZoneDefinition(strings.NewReader(`{"zone": "example.com.", "recrods": []}`)); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func TestReadZoneDefinitionFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "route53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "zone.json")
+	if err := ioutil.WriteFile(path, []byte(testZoneJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	def, err := ReadZoneDefinitionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(def.Records) != 4 {
+		t.Errorf("unexpected definition %+v", def)
+	}
+
+	if _, err := ReadZoneDefinitionFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("missing file: expected an error")
+	}
+}
+
+func TestZoneDefinitionWeights(t *testing.T) {
+	def, err := ParseZoneDefinition(strings.NewReader(`{"ttl": 60, "records": [
+		{"name": "w", "type": "A", "values": ["192.0.2.1"], "setIdentifier": "off", "weight": 0},
+		{"name": "w", "type": "A", "values": ["192.0.2.2"], "setIdentifier": "on", "weight": 3},
+		{"name": "plain", "type": "A", "values": ["192.0.2.3"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rrsets, err := def.RRSets("example.com.", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rrsets[0].Weight == nil || *rrsets[0].Weight != 0 || rrsets[1].Weight == nil || *rrsets[1].Weight != 3 || rrsets[2].Weight != nil {
+		t.Errorf("unexpected weights in %+v", rrsets)
+	}
+
+	// Weight 0 goes on the wire; unweighted record sets have no weight.
+	r53, requests := recordingServer(t, zoneResponses)
+	changes := []RRSetChange{}
+	for _, rrset := range rrsets {
+		changes = append(changes, RRSetChange{Action: "CREATE", RRSet: rrset})
+	}
+	if _, err := r53.ChangeRRSet("Z1", changes, ""); err != nil {
+		t.Fatal(err)
+	}
+	body := (*requests)[0]
+	if strings.Count(body, "<Weight>") != 2 || !strings.Contains(body, "<Weight>0</Weight>") {
+		t.Errorf("unexpected weights in %s", body)
+	}
+
+	def.Records[0].Weight = nil
+	if _, err := def.RRSets("example.com.", nil); err == nil {
+		t.Error("weighted record without a weight: expected an error")
+	}
+}
+
+func TestHealthCheckIDsByName(t *testing.T) {
+	// The checks are listed two to a page; check-3 has no Name tag.
+	names := map[string]string{"check-1": "web-1", "check-2": "web-2", "check-4": "web-4"}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /2013-04-01/healthcheck":
+			page := `<ListHealthChecksResponse><HealthChecks>
+				<HealthCheck><Id>check-1</Id></HealthCheck><HealthCheck><Id>check-2</Id></HealthCheck>
+				</HealthChecks><IsTruncated>true</IsTruncated><NextMarker>check-3</NextMarker></ListHealthChecksResponse>`
+			if r.URL.Query().Get("marker") == "check-3" {
+				page = `<ListHealthChecksResponse><HealthChecks>
+					<HealthCheck><Id>check-3</Id></HealthCheck><HealthCheck><Id>check-4</Id></HealthCheck>
+					</HealthChecks><IsTruncated>false</IsTruncated></ListHealthChecksResponse>`
+			}
+			w.Write([]byte(page))
+		case "POST /2013-04-01/tags/healthcheck":
+			data, _ := ioutil.ReadAll(r.Body)
+			sets := ""
+			for id, name := range names {
+				if strings.Contains(string(data), ">"+id+"<") {
+					sets += fmt.Sprintf(`<ResourceTagSet><ResourceType>healthcheck</ResourceType><ResourceId>%s</ResourceId>
+						<Tags><Tag><Key>Name</Key><Value>%s</Value></Tag></Tags></ResourceTagSet>`, id, name)
+				}
+			}
+			w.Write([]byte(`<ListTagsForResourcesResponse><ResourceTagSets>` + sets + `</ResourceTagSets></ListTagsForResourcesResponse>`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}
+	r53 := handlerServer(t, http.HandlerFunc(handler))
+
+	byName, err := r53.HealthCheckIDsByName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byName) != 3 || byName["web-1"] != "check-1" || byName["web-4"] != "check-4" {
+		t.Errorf("unexpected health checks %v", byName)
+	}
+
+	names["check-3"] = "web-1"
+	if _, err := r53.HealthCheckIDsByName(); err == nil || !strings.Contains(err.Error(), `"web-1"`) {
+		t.Errorf("duplicate names: unexpected error %v", err)
+	}
+}
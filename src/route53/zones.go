@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // XML RPC types.
@@ -15,15 +16,47 @@ type HostedZone struct {
 	Name                   string
 	CallerReference        string
 	Comment                string `xml:"Config>Comment"`
+	PrivateZone            bool   `xml:"Config>PrivateZone"`
 	ResourceRecordSetCount int
+
+	// Only filled in by CreateHostedZone and GetHostedZone.
+	NameServers []string `xml:"-"`
+	VPCs        []VPC    `xml:"-"`
+}
+
+// CreatedHostedZone is the result of creating a hosted zone. The embedded
+// zone can be used straight away, e.g. to add records.
+type CreatedHostedZone struct {
+	HostedZone
+	ChangeInfo ChangeInfo
+}
+
+// DeleteZoneOptions controls DeleteHostedZoneRecursive.
+type DeleteZoneOptions struct {
+	// Only list the record sets that would be deleted.
+	DryRun  bool
+	Comment string
+
+	// How often and how long to wait for the record deletions to sync, and
+	// for the record sets of deleted traffic policy instances to go.
+	// Defaults to every 5 seconds for up to 5 minutes.
+	PollEvery time.Duration
+	Timeout   time.Duration
+}
+
+type VPC struct {
+	VPCRegion string
+	VPCID     string `xml:"VPCId"`
 }
 
 type CreateHostedZoneRequest struct {
 	XMLName         xml.Name `xml:"CreateHostedZoneRequest"`
 	XMLNS           string   `xml:"xmlns,attr"`
 	Name            string
+	VPC             *VPC `xml:",omitempty"`
 	CallerReference string
 	Comment         string `xml:"HostedZoneConfig>Comment"`
+	DelegationSetID string `xml:"DelegationSetId,omitempty"`
 }
 
 type CreateHostedZoneResponse struct {
@@ -37,51 +70,171 @@ type GetHostedZoneResponse struct {
 	XMLName     xml.Name `xml:"GetHostedZoneResponse"`
 	HostedZone  HostedZone
 	NameServers []string `xml:"DelegationSet>NameServers>NameServer"`
+	VPCs        []VPC    `xml:"VPCs>VPC"`
 }
 
 type ListHostedZonesResponse struct {
-	XMLName     xml.Name `xml:"ListHostedZonesResponse"`
-	HostedZones []HostedZone
+	XMLName     xml.Name     `xml:"ListHostedZonesResponse"`
+	HostedZones []HostedZone `xml:"HostedZones>HostedZone"`
 	IsTruncated bool
 	Marker      string
 	NextMarker  string
 	MaxItems    uint
 }
 
+type ListHostedZonesByNameResponse struct {
+	XMLName          xml.Name     `xml:"ListHostedZonesByNameResponse"`
+	HostedZones      []HostedZone `xml:"HostedZones>HostedZone"`
+	DNSName          string
+	HostedZoneID     string `xml:"HostedZoneId"`
+	IsTruncated      bool
+	NextDNSName      string
+	NextHostedZoneID string `xml:"NextHostedZoneId"`
+	MaxItems         uint
+}
+
+type UpdateHostedZoneCommentRequest struct {
+	XMLName xml.Name `xml:"UpdateHostedZoneCommentRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Comment string
+}
+
+type UpdateHostedZoneCommentResponse struct {
+	XMLName    xml.Name `xml:"UpdateHostedZoneCommentResponse"`
+	HostedZone HostedZone
+}
+
+type GetHostedZoneCountResponse struct {
+	XMLName         xml.Name `xml:"GetHostedZoneCountResponse"`
+	HostedZoneCount int
+}
+
 type DeleteHostedZoneResponse struct {
 	ChangeInfo ChangeInfo
 }
 
+type AssociateVPCWithHostedZoneRequest struct {
+	XMLName xml.Name `xml:"AssociateVPCWithHostedZoneRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	VPC     VPC
+	Comment string `xml:",omitempty"`
+}
+
+type AssociateVPCWithHostedZoneResponse struct {
+	XMLName    xml.Name `xml:"AssociateVPCWithHostedZoneResponse"`
+	ChangeInfo ChangeInfo
+}
+
+type DisassociateVPCFromHostedZoneRequest struct {
+	XMLName xml.Name `xml:"DisassociateVPCFromHostedZoneRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	VPC     VPC
+	Comment string `xml:",omitempty"`
+}
+
+type DisassociateVPCFromHostedZoneResponse struct {
+	XMLName    xml.Name `xml:"DisassociateVPCFromHostedZoneResponse"`
+	ChangeInfo ChangeInfo
+}
+
+type CreateVPCAssociationAuthorizationRequest struct {
+	XMLName xml.Name `xml:"CreateVPCAssociationAuthorizationRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	VPC     VPC
+}
+
+type CreateVPCAssociationAuthorizationResponse struct {
+	XMLName      xml.Name `xml:"CreateVPCAssociationAuthorizationResponse"`
+	HostedZoneID string   `xml:"HostedZoneId"`
+	VPC          VPC
+}
+
+type DeleteVPCAssociationAuthorizationRequest struct {
+	XMLName xml.Name `xml:"DeleteVPCAssociationAuthorizationRequest"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	VPC     VPC
+}
+
+type DeleteVPCAssociationAuthorizationResponse struct {
+	XMLName xml.Name `xml:"DeleteVPCAssociationAuthorizationResponse"`
+}
+
+type ListVPCAssociationAuthorizationsResponse struct {
+	XMLName      xml.Name `xml:"ListVPCAssociationAuthorizationsResponse"`
+	HostedZoneID string   `xml:"HostedZoneId"`
+	NextToken    string
+	VPCs         []VPC `xml:"VPCs>VPC"`
+}
+
 // Route53 API requests.
 
-func (r53 *Route53) CreateHostedZone(name, reference, comment string) (ChangeInfo, error) {
+func (r53 *Route53) CreateHostedZone(name, reference, comment string) (CreatedHostedZone, error) {
+	xmlReq := &CreateHostedZoneRequest{
+		XMLNS:           "https://route53.amazonaws.com/doc/2013-04-01/",
+		Name:            name,
+		CallerReference: reference,
+		Comment:         comment,
+	}
+
+	return r53.createHostedZone(xmlReq)
+}
+
+// CreateHostedZoneWithDelegationSet creates a zone served by the name servers
+// of an existing reusable delegation set.
+func (r53 *Route53) CreateHostedZoneWithDelegationSet(name, reference, comment, delegationSetID string) (CreatedHostedZone, error) {
+	xmlReq := &CreateHostedZoneRequest{
+		XMLNS:           "https://route53.amazonaws.com/doc/2013-04-01/",
+		Name:            name,
+		CallerReference: reference,
+		Comment:         comment,
+		DelegationSetID: strings.Replace(delegationSetID, "/delegationset/", "", -1),
+	}
+
+	return r53.createHostedZone(xmlReq)
+}
+
+// CreatePrivateHostedZone creates a zone that is only visible from vpc.
+func (r53 *Route53) CreatePrivateHostedZone(name, reference, comment string, vpc VPC) (CreatedHostedZone, error) {
 	xmlReq := &CreateHostedZoneRequest{
-		XMLNS:           "https://route53.amazonaws.com/doc/2012-12-12/",
+		XMLNS:           "https://route53.amazonaws.com/doc/2013-04-01/",
 		Name:            name,
+		VPC:             &vpc,
 		CallerReference: reference,
 		Comment:         comment,
 	}
 
+	return r53.createHostedZone(xmlReq)
+}
+
+func (r53 *Route53) createHostedZone(xmlReq *CreateHostedZoneRequest) (CreatedHostedZone, error) {
 	req := request{
 		method: "POST",
-		path:   "/2012-12-12/hostedzone",
+		path:   "/2013-04-01/hostedzone",
 		body:   xmlReq,
 	}
 
 	xmlRes := &CreateHostedZoneResponse{}
 
 	if err := r53.run(req, xmlRes); err != nil {
-		return ChangeInfo{}, err
+		return CreatedHostedZone{}, err
+	}
+	xmlRes.HostedZone.r53 = r53
+	xmlRes.HostedZone.NameServers = xmlRes.NameServers
+	if xmlReq.VPC != nil {
+		xmlRes.HostedZone.VPCs = []VPC{*xmlReq.VPC}
 	}
 	xmlRes.ChangeInfo.r53 = r53
 
-	return xmlRes.ChangeInfo, nil
+	return CreatedHostedZone{
+		HostedZone: xmlRes.HostedZone,
+		ChangeInfo: xmlRes.ChangeInfo,
+	}, nil
 }
 
 func (r53 *Route53) GetHostedZone(id string) (HostedZone, error) {
 	req := request{
 		method: "GET",
-		path:   fmt.Sprintf("/2012-12-12/hostedzone/%s", strings.Replace(id, "/hostedzone/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s", strings.Replace(id, "/hostedzone/", "", -1)),
 	}
 
 	xmlRes := &GetHostedZoneResponse{}
@@ -90,6 +243,8 @@ func (r53 *Route53) GetHostedZone(id string) (HostedZone, error) {
 		return HostedZone{}, err
 	}
 	xmlRes.HostedZone.r53 = r53
+	xmlRes.HostedZone.NameServers = xmlRes.NameServers
+	xmlRes.HostedZone.VPCs = xmlRes.VPCs
 
 	return xmlRes.HostedZone, nil
 }
@@ -97,7 +252,7 @@ func (r53 *Route53) GetHostedZone(id string) (HostedZone, error) {
 func (r53 *Route53) ListHostedZones() ([]HostedZone, error) {
 	req := request{
 		method: "GET",
-		path:   "/2012-12-12/hostedzone",
+		path:   "/2013-04-01/hostedzone",
 	}
 
 	xmlRes := &ListHostedZonesResponse{}
@@ -114,23 +269,144 @@ func (r53 *Route53) ListHostedZones() ([]HostedZone, error) {
 			"marker": []string{xmlRes.NextMarker},
 		}
 
+		xmlRes = &ListHostedZonesResponse{}
 		if err := r53.run(req, xmlRes); err != nil {
 			return []HostedZone{}, err
 		}
 		zones = append(zones, xmlRes.HostedZones...)
 	}
 
-	for _, zone := range zones {
-		zone.r53 = r53
+	for i := range zones {
+		zones[i].r53 = r53
+	}
+
+	return zones, nil
+}
+
+// ListHostedZonesByName lists zones in the order Route53 sorts them by name
+// (labels reversed), starting with the first zone at or after dnsName.
+func (r53 *Route53) ListHostedZonesByName(dnsName string) ([]HostedZone, error) {
+	zones := []HostedZone{}
+
+	xmlRes := &ListHostedZonesByNameResponse{
+		IsTruncated: true,
+		NextDNSName: dnsName,
+	}
+
+	for xmlRes.IsTruncated {
+		page, err := r53.listHostedZonesByName(xmlRes.NextDNSName, xmlRes.NextHostedZoneID, 0)
+		if err != nil {
+			return []HostedZone{}, err
+		}
+		xmlRes = page
+		zones = append(zones, xmlRes.HostedZones...)
 	}
 
 	return zones, nil
 }
 
+func (r53 *Route53) listHostedZonesByName(dnsName, zoneID string, maxItems uint) (*ListHostedZonesByNameResponse, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/hostedzonesbyname",
+		params: &url.Values{},
+	}
+	if dnsName != "" {
+		req.params.Set("dnsname", dnsName)
+	}
+	if zoneID != "" {
+		req.params.Set("hostedzoneid", strings.Replace(zoneID, "/hostedzone/", "", -1))
+	}
+	if maxItems > 0 {
+		req.params.Set("maxitems", fmt.Sprint(maxItems))
+	}
+
+	xmlRes := &ListHostedZonesByNameResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return nil, err
+	}
+
+	for i := range xmlRes.HostedZones {
+		xmlRes.HostedZones[i].r53 = r53
+	}
+
+	return xmlRes, nil
+}
+
+// FindZoneForName returns the most specific hosted zone that would be
+// authoritative for fqdn. When both a public and a private zone have that
+// name, preferPrivate picks between them.
+func (r53 *Route53) FindZoneForName(fqdn string, preferPrivate bool) (HostedZone, error) {
+	labels := strings.Split(strings.TrimSuffix(CanonicalName(fqdn), "."), ".")
+
+	for i := range labels {
+		name := strings.Join(labels[i:], ".") + "."
+
+		page, err := r53.listHostedZonesByName(name, "", 100)
+		if err != nil {
+			return HostedZone{}, err
+		}
+
+		var found *HostedZone
+		for j := range page.HostedZones {
+			zone := &page.HostedZones[j]
+			if !NamesEqual(zone.Name, name) {
+				continue
+			}
+			if found == nil || zone.PrivateZone == preferPrivate {
+				found = zone
+			}
+		}
+		if found != nil {
+			return *found, nil
+		}
+	}
+
+	return HostedZone{}, fmt.Errorf("no hosted zone found for %s", fqdn)
+}
+
+func (r53 *Route53) UpdateHostedZoneComment(id, comment string) (HostedZone, error) {
+	xmlReq := &UpdateHostedZoneCommentRequest{
+		XMLNS:   "https://route53.amazonaws.com/doc/2013-04-01/",
+		Comment: comment,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s", strings.Replace(id, "/hostedzone/", "", -1)),
+		body:   xmlReq,
+	}
+
+	xmlRes := &UpdateHostedZoneCommentResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return HostedZone{}, err
+	}
+	xmlRes.HostedZone.r53 = r53
+
+	return xmlRes.HostedZone, nil
+}
+
+func (r53 *Route53) GetHostedZoneCount() (int, error) {
+	req := request{
+		method: "GET",
+		path:   "/2013-04-01/hostedzonecount",
+	}
+
+	xmlRes := &GetHostedZoneCountResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return 0, err
+	}
+
+	return xmlRes.HostedZoneCount, nil
+}
+
 func (r53 *Route53) DeleteHostedZone(id string) (ChangeInfo, error) {
 	req := request{
 		method: "DELETE",
-		path:   fmt.Sprintf("/2012-12-12/hostedzone/%s", strings.Replace(id, "/hostedzone/", "", -1)),
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s", strings.Replace(id, "/hostedzone/", "", -1)),
 	}
 
 	xmlRes := &DeleteHostedZoneResponse{}
@@ -142,3 +418,273 @@ func (r53 *Route53) DeleteHostedZone(id string) (ChangeInfo, error) {
 
 	return xmlRes.ChangeInfo, nil
 }
+
+// DeleteHostedZoneRecursive deletes every record set in a zone except the
+// apex SOA and NS records, waits for those changes to sync and then deletes
+// the zone itself. Record sets created by traffic policy instances are
+// removed by deleting the instances, which Route53 does in the background, so
+// the zone is polled until they are gone. It returns the record sets that
+// were (or, in a dry run, would be) deleted.
+func (r53 *Route53) DeleteHostedZoneRecursive(id string, opts DeleteZoneOptions) (ChangeInfo, []RRSet, error) {
+	if opts.PollEvery == 0 {
+		opts.PollEvery = 5 * time.Second
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+
+	zone, err := r53.GetHostedZone(id)
+	if err != nil {
+		return ChangeInfo{}, []RRSet{}, err
+	}
+
+	rrsets, err := zone.ListRRSets()
+	if err != nil {
+		return ChangeInfo{}, []RRSet{}, err
+	}
+
+	doomed := []RRSet{}
+	changes := []RRSetChange{}
+	instances := []string{}
+	seen := map[string]bool{}
+	for _, rrset := range rrsets {
+		if isZoneApexRecord(rrset, zone.Name) {
+			continue
+		}
+		doomed = append(doomed, rrset)
+		if isTrafficPolicyRecord(rrset) {
+			if !seen[rrset.TrafficPolicyInstanceID] {
+				instances = append(instances, rrset.TrafficPolicyInstanceID)
+				seen[rrset.TrafficPolicyInstanceID] = true
+			}
+			continue
+		}
+		changes = append(changes, RRSetChange{Action: "DELETE", RRSet: rrset})
+	}
+
+	if opts.DryRun {
+		return ChangeInfo{}, doomed, nil
+	}
+
+	for _, instance := range instances {
+		if err := r53.DeleteTrafficPolicyInstance(instance); err != nil {
+			return ChangeInfo{}, doomed, err
+		}
+	}
+
+	infos, err := zone.changeRRSetBatches(changes, opts.Comment, rrsets)
+	if err != nil {
+		return ChangeInfo{}, doomed, err
+	}
+	for _, info := range infos {
+		if err := <-info.PollForSync(opts.PollEvery, opts.Timeout); err != nil {
+			return ChangeInfo{}, doomed, err
+		}
+	}
+	if len(instances) > 0 {
+		if err := zone.waitForInstanceRecords(opts.PollEvery, opts.Timeout); err != nil {
+			return ChangeInfo{}, doomed, err
+		}
+	}
+
+	change, err := r53.DeleteHostedZone(id)
+	if err != nil {
+		return ChangeInfo{}, doomed, err
+	}
+
+	return change, doomed, nil
+}
+
+// waitForInstanceRecords polls the zone until it holds no record sets of
+// traffic policy instances.
+func (z *HostedZone) waitForInstanceRecords(every, timeout time.Duration) error {
+	toutC := time.After(timeout)
+	for {
+		rrsets, err := z.ListRRSets()
+		if err != nil {
+			return err
+		}
+		left := 0
+		for _, rrset := range rrsets {
+			if isTrafficPolicyRecord(rrset) {
+				left++
+			}
+		}
+		if left == 0 {
+			return nil
+		}
+
+		select {
+		case <-time.After(every):
+		case <-toutC:
+			return fmt.Errorf("timed out waiting for %d traffic policy record sets to be deleted", left)
+		}
+	}
+}
+
+func (r53 *Route53) AssociateVPCWithHostedZone(zoneID string, vpc VPC, comment string) (ChangeInfo, error) {
+	xmlReq := &AssociateVPCWithHostedZoneRequest{
+		XMLNS:   "https://route53.amazonaws.com/doc/2013-04-01/",
+		VPC:     vpc,
+		Comment: comment,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/associatevpc", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		body:   xmlReq,
+	}
+
+	xmlRes := &AssociateVPCWithHostedZoneResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return ChangeInfo{}, err
+	}
+	xmlRes.ChangeInfo.r53 = r53
+
+	return xmlRes.ChangeInfo, nil
+}
+
+func (r53 *Route53) DisassociateVPCFromHostedZone(zoneID string, vpc VPC, comment string) (ChangeInfo, error) {
+	xmlReq := &DisassociateVPCFromHostedZoneRequest{
+		XMLNS:   "https://route53.amazonaws.com/doc/2013-04-01/",
+		VPC:     vpc,
+		Comment: comment,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/disassociatevpc", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		body:   xmlReq,
+	}
+
+	xmlRes := &DisassociateVPCFromHostedZoneResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return ChangeInfo{}, err
+	}
+	xmlRes.ChangeInfo.r53 = r53
+
+	return xmlRes.ChangeInfo, nil
+}
+
+func (r53 *Route53) CreateVPCAssociationAuthorization(zoneID string, vpc VPC) error {
+	xmlReq := &CreateVPCAssociationAuthorizationRequest{
+		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		VPC:   vpc,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/authorizevpcassociation", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		body:   xmlReq,
+	}
+
+	xmlRes := &CreateVPCAssociationAuthorizationResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r53 *Route53) DeleteVPCAssociationAuthorization(zoneID string, vpc VPC) error {
+	xmlReq := &DeleteVPCAssociationAuthorizationRequest{
+		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		VPC:   vpc,
+	}
+
+	req := request{
+		method: "POST",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/deauthorizevpcassociation", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+		body:   xmlReq,
+	}
+
+	xmlRes := &DeleteVPCAssociationAuthorizationResponse{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r53 *Route53) ListVPCAssociationAuthorizations(zoneID string) ([]VPC, error) {
+	req := request{
+		method: "GET",
+		path:   fmt.Sprintf("/2013-04-01/hostedzone/%s/authorizevpcassociation", strings.Replace(zoneID, "/hostedzone/", "", -1)),
+	}
+
+	xmlRes := &ListVPCAssociationAuthorizationsResponse{}
+
+	vpcs := []VPC{}
+
+	if err := r53.run(req, xmlRes); err != nil {
+		return []VPC{}, err
+	}
+	vpcs = append(vpcs, xmlRes.VPCs...)
+
+	for xmlRes.NextToken != "" {
+		req.params = &url.Values{
+			"nexttoken": []string{xmlRes.NextToken},
+		}
+
+		xmlRes = &ListVPCAssociationAuthorizationsResponse{}
+		if err := r53.run(req, xmlRes); err != nil {
+			return []VPC{}, err
+		}
+		vpcs = append(vpcs, xmlRes.VPCs...)
+	}
+
+	return vpcs, nil
+}
+
+// AssociateVPCAcrossAccounts associates a VPC owned by one account with a
+// private zone owned by another. zoneAccount must be a client for the account
+// that owns the zone and vpcAccount one for the account that owns the VPC.
+// The authorization is removed again once the association has been made, or
+// has failed.
+func AssociateVPCAcrossAccounts(zoneAccount, vpcAccount *Route53, zoneID string, vpc VPC, comment string) (ChangeInfo, error) {
+	if err := zoneAccount.CreateVPCAssociationAuthorization(zoneID, vpc); err != nil {
+		return ChangeInfo{}, err
+	}
+
+	change, err := vpcAccount.AssociateVPCWithHostedZone(zoneID, vpc, comment)
+	if err != nil {
+		if derr := zoneAccount.DeleteVPCAssociationAuthorization(zoneID, vpc); derr != nil {
+			return ChangeInfo{}, fmt.Errorf("%s (removing the authorization also failed: %s)", err, derr)
+		}
+		return ChangeInfo{}, err
+	}
+
+	if err := zoneAccount.DeleteVPCAssociationAuthorization(zoneID, vpc); err != nil {
+		return change, err
+	}
+
+	return change, nil
+}
+
+// Convenience functions on AWS APIs.
+
+func (z *HostedZone) UpdateComment(comment string) error {
+	zone, err := z.r53.UpdateHostedZoneComment(z.ID, comment)
+	if err != nil {
+		return err
+	}
+	z.Comment = zone.Comment
+
+	return nil
+}
+
+func (z *HostedZone) Delete() (ChangeInfo, error) {
+	return z.r53.DeleteHostedZone(z.ID)
+}
+
+func (z *HostedZone) AssociateVPC(vpc VPC, comment string) (ChangeInfo, error) {
+	return z.r53.AssociateVPCWithHostedZone(z.ID, vpc, comment)
+}
+
+func (z *HostedZone) DisassociateVPC(vpc VPC, comment string) (ChangeInfo, error) {
+	return z.r53.DisassociateVPCFromHostedZone(z.ID, vpc, comment)
+}
@@ -0,0 +1,36 @@
+package route53
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssociateVPCAcrossAccountsCleansUp(t *testing.T) {
+	zone := "/2013-04-01/hostedzone/Z1/"
+	vpc := VPC{VPCRegion: "us-east-1", VPCID: "vpc-1"}
+
+	for _, associated := range []bool{true, false} {
+		response := `<AssociateVPCWithHostedZoneResponse>` + testChangeInfo + `</AssociateVPCWithHostedZoneResponse>`
+		if !associated {
+			response = `<ErrorResponse><Error><Code>NotAuthorizedException</Code><Message>no</Message></Error></ErrorResponse>`
+		}
+		r53, requests := recordingServer(t, map[string]string{
+			"POST " + zone + "authorizevpcassociation":   `<CreateVPCAssociationAuthorizationResponse/>`,
+			"POST " + zone + "associatevpc":              response,
+			"POST " + zone + "deauthorizevpcassociation": `<DeleteVPCAssociationAuthorizationResponse/>`,
+		})
+
+		_, err := AssociateVPCAcrossAccounts(r53, r53, "Z1", vpc, "")
+		if associated && err != nil {
+			t.Error(err)
+		}
+		if !associated && (err == nil || !strings.Contains(err.Error(), "NotAuthorizedException")) {
+			t.Errorf("unexpected error %v", err)
+		}
+
+		last := (*requests)[len(*requests)-1]
+		if !strings.HasPrefix(last, "POST "+zone+"deauthorizevpcassociation") {
+			t.Errorf("associated %v: authorization not removed, last request %s", associated, last)
+		}
+	}
+}
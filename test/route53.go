@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/crowdmob/goamz/aws"
 	"github.com/jessevdk/go-flags"
 	"os"
+	"path/filepath"
 	"route53"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -28,7 +32,7 @@ func (c *ChangeCommand) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("change %s submitted %s is %s", change.Id, change.SubmittedAt, change.Status)
+	fmt.Printf("change %s submitted %s is %s", change.ID, change.SubmittedAt, change.Status)
 	return nil
 }
 
@@ -38,6 +42,11 @@ type ZoneCommand struct {
 	Name      string `short:"n" long:"name" description:"zone name"`
 	Reference string `short:"r" long:"reference" description:"caller reference"`
 	Comment   string `short:"c" long:"comment" description:"comment string"`
+	Private   bool   `long:"private" description:"prefer private zone when looking up by name"`
+	Force     bool   `long:"force" description:"delete all records before deleting zone"`
+	DryRun    bool   `long:"dry-run" description:"only list records that would change"`
+	File      string `short:"f" long:"file" description:"BIND zone file to import"`
+	Overwrite bool   `long:"overwrite" description:"replace existing records on import"`
 }
 
 func (z *ZoneCommand) Execute(args []string) error {
@@ -45,15 +54,92 @@ func (z *ZoneCommand) Execute(args []string) error {
 	case "list-zones":
 		r53.ListHostedZones()
 	case "get-zone":
-		if z.Id == "" {
-			fmt.Fprintln(os.Stderr, "error: no id specified")
+		if z.Id == "" && z.Name == "" {
+			fmt.Fprintln(os.Stderr, "error: no id or name specified")
 			os.Exit(255)
 		}
+		if z.Id == "" {
+			zone, err := r53.FindZoneForName(z.Name, z.Private)
+			if err != nil {
+				return err
+			}
+			z.Id = zone.ID
+		}
 		r53.GetHostedZone(z.Id)
 	case "add-zone":
-		r53.CreateHostedZone(z.Name, z.Reference, z.Comment)
+		zone, err := r53.CreateHostedZone(z.Name, z.Reference, z.Comment)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("zone %s created by change %s\n", zone.ID, zone.ChangeInfo.ID)
+		for _, ns := range zone.NameServers {
+			fmt.Println(ns)
+		}
+	case "update-zone":
+		if z.Id == "" {
+			fmt.Fprintln(os.Stderr, "error: no id specified")
+			os.Exit(255)
+		}
+		if _, err := r53.UpdateHostedZoneComment(z.Id, z.Comment); err != nil {
+			return err
+		}
+	case "export-zone":
+		if z.Id == "" {
+			fmt.Fprintln(os.Stderr, "error: no id specified")
+			os.Exit(255)
+		}
+		zone, err := r53.GetHostedZone(z.Id)
+		if err != nil {
+			return err
+		}
+		return zone.ExportZoneFile(os.Stdout)
+	case "import-zone":
+		if z.Id == "" || z.File == "" {
+			fmt.Fprintln(os.Stderr, "error: no id or file specified")
+			os.Exit(255)
+		}
+		zone, err := r53.GetHostedZone(z.Id)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(z.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		opts := route53.ImportZoneOptions{
+			Overwrite:  z.Overwrite,
+			DryRun:     z.DryRun,
+			Comment:    z.Comment,
+			IncludeDir: filepath.Dir(z.File),
+		}
+		changes, _, err := zone.ImportZoneFile(f, opts)
+		for _, change := range changes {
+			fmt.Printf("%s %s %s\n", change.Action, change.RRSet.Name, change.RRSet.Type)
+		}
+		if err != nil {
+			return err
+		}
+	case "count-zones":
+		count, err := r53.GetHostedZoneCount()
+		if err != nil {
+			return err
+		}
+		fmt.Println(count)
 	case "delete-zone":
-		r53.DeleteHostedZone(z.Id)
+		// A dry run lists what --force would delete and never deletes the zone.
+		if !z.Force && !z.DryRun {
+			_, err := r53.DeleteHostedZone(z.Id)
+			return err
+		}
+		opts := route53.DeleteZoneOptions{DryRun: z.DryRun, Comment: z.Comment}
+		_, rrsets, err := r53.DeleteHostedZoneRecursive(z.Id, opts)
+		for _, rrset := range rrsets {
+			fmt.Printf("delete %s %s %s\n", rrset.Name, rrset.Type, rrset.SetIdentifier)
+		}
+		if err != nil {
+			return err
+		}
 	default:
 		fmt.Fprintln(os.Stderr, "error: unknown zone command")
 		os.Exit(255)
@@ -73,13 +159,21 @@ type RRSetCommand struct {
 	SetIdentifier string   `short:"i" long:"id" description:"record identifier"`
 
 	// Weight Syntax
-	Weight uint8 `long:"weight" description:"record weight [0-255]"`
+	Weight *uint8 `long:"weight" description:"record weight [0-255]"`
 
 	// Failover Syntax
 	Failover string `long:"failover" description:"primary or secondary"`
 
 	// Latency Syntax
 	Region string `long:"region" description:"ec2 region name"`
+
+	// Geolocation Syntax
+	Continent   string `long:"continent" description:"continent code (AF, AN, AS, EU, NA, OC, SA)"`
+	Country     string `long:"country" description:"country code, or * for the default location"`
+	Subdivision string `long:"subdivision" description:"subdivision code within the country"`
+
+	// Multivalue Answer Syntax
+	MultiValue bool `long:"multivalue" description:"multivalue answer record"`
 }
 
 func (r *RRSetCommand) Execute(args []string) error {
@@ -87,11 +181,25 @@ func (r *RRSetCommand) Execute(args []string) error {
 		Name:          r.Name,
 		Type:          r.Type,
 		TTL:           r.TTL,
-		Values:        r.Values,
 		SetIdentifier: r.SetIdentifier,
 		Weight:        r.Weight,
 		Failover:      r.Failover,
 		Region:        r.Region,
+
+		MultiValueAnswer: r.MultiValue,
+	}
+	if r.Continent != "" || r.Country != "" || r.Subdivision != "" {
+		rrset.GeoLocation = &route53.GeoLocation{
+			ContinentCode:   strings.ToUpper(r.Continent),
+			CountryCode:     strings.ToUpper(r.Country),
+			SubdivisionCode: strings.ToUpper(r.Subdivision),
+		}
+	}
+	if len(r.Values) > 0 {
+		rrset.ResourceRecords = &route53.ResourceRecords{}
+		for _, value := range r.Values {
+			rrset.ResourceRecords.ResourceRecord = append(rrset.ResourceRecords.ResourceRecord, route53.ResourceRecord{Value: value})
+		}
 	}
 
 	switch r.Cmd {
@@ -153,6 +261,172 @@ func (c *HealthCheckCommand) Execute(args []string) error {
 	return nil
 }
 
+type TagCommand struct {
+	Cmd  string
+	Type string   `short:"t" long:"type" description:"hostedzone or healthcheck" default:"hostedzone"`
+	Id   string   `short:"i" long:"id" description:"zone or health check ID"`
+	Tags []string `short:"k" long:"tag" description:"key=value to tag, or key to untag"`
+}
+
+func (t *TagCommand) Execute(args []string) error {
+	if t.Id == "" {
+		fmt.Fprintln(os.Stderr, "error: no id specified")
+		os.Exit(255)
+	}
+
+	switch t.Cmd {
+	case "list-tags":
+		tags, err := r53.ListTagsForResource(t.Type, t.Id)
+		if err != nil {
+			return err
+		}
+		keys := []string{}
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s=%s\n", key, tags[key])
+		}
+	case "tag":
+		tags := route53.Tags{}
+		for _, tag := range t.Tags {
+			kv := strings.SplitN(tag, "=", 2)
+			if len(kv) != 2 {
+				fmt.Fprintf(os.Stderr, "error: tag %q is not key=value\n", tag)
+				os.Exit(255)
+			}
+			tags[kv[0]] = kv[1]
+		}
+		return r53.ChangeTagsForResource(t.Type, t.Id, tags, nil)
+	case "untag":
+		return r53.ChangeTagsForResource(t.Type, t.Id, nil, t.Tags)
+	default:
+		fmt.Fprintln(os.Stderr, "error: unknown tag command")
+		os.Exit(255)
+	}
+
+	return nil
+}
+
+type PlanCommand struct {
+	Cmd      string
+	File     string `short:"f" long:"file" description:"zone definition file (JSON)"`
+	NoDelete bool   `long:"no-delete" description:"keep records missing from the definition"`
+	Comment  string `short:"c" long:"comment" description:"comment change"`
+	Owner    string `long:"owner" description:"only manage records owned by this owner ID"`
+	Force    bool   `long:"force" description:"change records owned by someone else"`
+	Wait     bool   `short:"w" long:"wait" description:"wait for the changes to be in sync"`
+}
+
+func (p *PlanCommand) Execute(args []string) error {
+	if p.File == "" {
+		fmt.Fprintln(os.Stderr, "error: no file specified")
+		os.Exit(255)
+	}
+	if p.Owner != "" {
+		r53.Registry = &route53.Registry{OwnerID: p.Owner, Force: p.Force}
+	}
+
+	def, err := route53.ReadZoneDefinitionFile(p.File)
+	if err != nil {
+		return err
+	}
+
+	var zone route53.HostedZone
+	if def.ZoneID != "" {
+		zone, err = r53.GetHostedZone(def.ZoneID)
+	} else {
+		zone, err = r53.FindZoneForName(def.Zone, def.Private)
+	}
+	if err != nil {
+		return err
+	}
+
+	checks := map[string]string{}
+	if len(def.HealthChecks()) > 0 {
+		if checks, err = r53.HealthCheckIDsByName(); err != nil {
+			return err
+		}
+	}
+
+	rrsets, err := def.RRSets(zone.Name, checks)
+	if err != nil {
+		return err
+	}
+	if err := route53.ValidateRRSets(zone.Name, rrsets); err != nil {
+		return err
+	}
+
+	plan, err := zone.Plan(rrsets, route53.PlanOptions{NoDelete: p.NoDelete})
+	if err != nil {
+		return err
+	}
+	if summary := plan.String(); summary != "" {
+		fmt.Println(summary)
+	}
+	if plan.Empty() {
+		fmt.Println("zone is up to date")
+		return nil
+	}
+
+	if p.Cmd == "apply" {
+		changes, err := r53.ApplyPlan(plan, p.Comment)
+		for _, change := range changes {
+			fmt.Printf("change %s is %s\n", change.ID, change.Status)
+		}
+		if err != nil {
+			return err
+		}
+
+		if p.Wait {
+			_, err := r53.WaitForChanges(context.Background(), changes, route53.WaitOptions{
+				Progress: func(result route53.ChangeResult, done, total int) {
+					fmt.Printf("[%d/%d] change %s is %s\n", done, total, result.ID, result.Status)
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type TestAnswerCommand struct {
+	ZoneId   string   `short:"z" long:"zone" description:"zone to query"`
+	Name     string   `short:"n" long:"name" description:"dns domain name"`
+	Type     string   `short:"t" long:"type" description:"dns record type"`
+	Resolver string   `long:"resolver" description:"resolver IP the query comes from"`
+	Subnet   string   `long:"subnet" description:"EDNS0 client subnet (IP or CIDR)"`
+	Expect   []string `short:"e" long:"expect" description:"expected answer value; exit non-zero on mismatch"`
+}
+
+func (t *TestAnswerCommand) Execute(args []string) error {
+	if t.ZoneId == "" || t.Name == "" || t.Type == "" {
+		fmt.Fprintln(os.Stderr, "error: zone, name and type are required")
+		os.Exit(255)
+	}
+
+	answer, err := r53.TestDNSAnswer(t.ZoneId, t.Name, strings.ToUpper(t.Type), t.Resolver, t.Subnet)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s %s from %s over %s\n", answer.RecordName, answer.RecordType, answer.ResponseCode, answer.Nameserver, answer.Protocol)
+	for _, data := range answer.RecordData {
+		fmt.Println(data)
+	}
+
+	if len(t.Expect) > 0 && !answer.Matches(t.Expect) {
+		fmt.Fprintf(os.Stderr, "error: expected %s\n", strings.Join(t.Expect, ", "))
+		os.Exit(255)
+	}
+
+	return nil
+}
+
 func NewClient() *Route53Client {
 	c := &Route53Client{flags.NewParser(nil, flags.Default)}
 
@@ -161,17 +435,29 @@ func NewClient() *Route53Client {
 	c.AddCommand("list-zones", "list route53 zones", "", &ZoneCommand{Cmd: "list-zones"})
 	c.AddCommand("get-zone", "inspect route53 zone", "", &ZoneCommand{Cmd: "get-zone"})
 	c.AddCommand("add-zone", "add zone to route53", "", &ZoneCommand{Cmd: "add-zone"})
+	c.AddCommand("update-zone", "update zone comment", "", &ZoneCommand{Cmd: "update-zone"})
+	c.AddCommand("count-zones", "count route53 zones", "", &ZoneCommand{Cmd: "count-zones"})
+	c.AddCommand("export-zone", "print zone in BIND format", "", &ZoneCommand{Cmd: "export-zone"})
+	c.AddCommand("import-zone", "import BIND zone file", "", &ZoneCommand{Cmd: "import-zone"})
 	c.AddCommand("delete-zone", "delete zone from route53", "", &ZoneCommand{Cmd: "delete-zone"})
 
 	c.AddCommand("list-rrsets", "list resource record sets", "", &RRSetCommand{Cmd: "list-rrsets"})
 	c.AddCommand("add-rrset", "add resource record to zone", "", &RRSetCommand{Cmd: "add-rrset"})
 	c.AddCommand("delete-rrset", "delete resource record from zone", "", &RRSetCommand{Cmd: "delete-rrset"})
+	c.AddCommand("test-answer", "show what route53 would answer for a query", "", &TestAnswerCommand{})
 
 	c.AddCommand("list-checks", "list health checks", "", &HealthCheckCommand{Cmd: "list-checks"})
 	c.AddCommand("get-check", "inspect health check", "", &HealthCheckCommand{Cmd: "get-check"})
 	c.AddCommand("add-check", "add health check", "", &HealthCheckCommand{Cmd: "add-check"})
 	c.AddCommand("delete-check", "delete health check", "", &HealthCheckCommand{Cmd: "delete-check"})
 
+	c.AddCommand("plan", "show changes needed to match zone definition", "", &PlanCommand{Cmd: "plan"})
+	c.AddCommand("apply", "apply zone definition", "", &PlanCommand{Cmd: "apply"})
+
+	c.AddCommand("list-tags", "list tags on zone or health check", "", &TagCommand{Cmd: "list-tags"})
+	c.AddCommand("tag", "add tags to zone or health check", "", &TagCommand{Cmd: "tag"})
+	c.AddCommand("untag", "remove tags from zone or health check", "", &TagCommand{Cmd: "untag"})
+
 	return c
 }
 